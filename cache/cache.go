@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AllDatasets is the dataset ID used for cached responses that hold more than one dataset,
+// which are invalidated when any dataset is written
+const AllDatasets = "*"
+
+// Cache is an in-process TTL cache of response bodies, grouped by the dataset they describe so that they
+// can be invalidated when that dataset is written
+type Cache struct {
+	ttl        time.Duration
+	mu         sync.RWMutex
+	entries    map[string]entry
+	generation uint64
+	hits       uint64
+	misses     uint64
+	now        func() time.Time
+}
+
+type entry struct {
+	value     []byte
+	datasetID string
+	expires   time.Time
+}
+
+// Stats holds the cache hit and miss counts
+type Stats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// New creates a cache holding values for the given TTL. A TTL of 0 disables the cache
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: map[string]entry{},
+		now:     time.Now,
+	}
+}
+
+// Enabled reports whether values are cached
+func (c *Cache) Enabled() bool {
+	return c.ttl > 0
+}
+
+// Key builds a cache key from the user access token, collection ID and route. The access token is hashed
+// so that cached responses are only returned to the user the dataset API authorised them for
+func Key(accessToken, collectionID, route string) string {
+	token := sha256.Sum256([]byte(accessToken))
+	return hex.EncodeToString(token[:]) + "|" + collectionID + "|" + route
+}
+
+// Get returns the value for the key if it has not expired, recording a hit or a miss
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || !c.now().Before(e.expires) {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	return e.value, true
+}
+
+// Generation returns a count of the invalidations made. It is read before getting a value to cache, so that a value
+// got before an invalidation is not stored after it
+func (c *Cache) Generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// Set stores the value for the key against the dataset it describes, removing any expired values. The value is not
+// stored if the cache has been invalidated since the given generation was read, as it may be stale
+func (c *Cache) Set(key, datasetID string, value []byte, generation uint64) {
+	if !c.Enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = entry{
+		value:     value,
+		datasetID: datasetID,
+		expires:   now.Add(c.ttl),
+	}
+}
+
+// InvalidateDataset removes the values describing the dataset, along with the values describing all datasets
+func (c *Cache) InvalidateDataset(datasetID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for k, e := range c.entries {
+		if e.datasetID == datasetID || e.datasetID == AllDatasets {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// InvalidateAllDatasets removes every value describing one or more datasets
+func (c *Cache) InvalidateAllDatasets() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for k, e := range c.entries {
+		if e.datasetID != "" {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Stats returns the hit and miss counts since the cache was created, and the number of values held
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	return Stats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: entries,
+	}
+}
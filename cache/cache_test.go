@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitCache(t *testing.T) {
+	t.Parallel()
+
+	Convey("Given a cache", t, func() {
+		now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := New(time.Minute)
+		c.now = func() time.Time { return now }
+
+		Convey("When a value is set", func() {
+			c.Set("key", "cpih01", []byte("value"), c.Generation())
+
+			Convey("Then it is returned and counted as a hit", func() {
+				value, ok := c.Get("key")
+				So(ok, ShouldBeTrue)
+				So(string(value), ShouldEqual, "value")
+				So(c.Stats(), ShouldResemble, Stats{Hits: 1, Misses: 0, Entries: 1})
+			})
+
+			Convey("Then it is not returned once it has expired", func() {
+				now = now.Add(time.Minute)
+				_, ok := c.Get("key")
+				So(ok, ShouldBeFalse)
+				So(c.Stats().Misses, ShouldEqual, 1)
+			})
+
+			Convey("Then expired values are removed when another value is set", func() {
+				now = now.Add(time.Minute)
+				c.Set("other", "cpih01", []byte("value"), c.Generation())
+				So(c.Stats().Entries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a dataset is invalidated", func() {
+			c.Set("dataset", "cpih01", []byte("value"), c.Generation())
+			c.Set("other-dataset", "TS009", []byte("value"), c.Generation())
+			c.Set("all-datasets", AllDatasets, []byte("value"), c.Generation())
+			c.Set("topics", "", []byte("value"), c.Generation())
+			c.InvalidateDataset("cpih01")
+
+			Convey("Then the values for the dataset and all datasets are removed", func() {
+				_, ok := c.Get("dataset")
+				So(ok, ShouldBeFalse)
+				_, ok = c.Get("all-datasets")
+				So(ok, ShouldBeFalse)
+				_, ok = c.Get("other-dataset")
+				So(ok, ShouldBeTrue)
+				_, ok = c.Get("topics")
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("When all datasets are invalidated", func() {
+			c.Set("dataset", "cpih01", []byte("value"), c.Generation())
+			c.Set("other-dataset", "TS009", []byte("value"), c.Generation())
+			c.Set("all-datasets", AllDatasets, []byte("value"), c.Generation())
+			c.Set("topics", "", []byte("value"), c.Generation())
+			c.InvalidateAllDatasets()
+
+			Convey("Then only the values not describing a dataset are kept", func() {
+				_, ok := c.Get("dataset")
+				So(ok, ShouldBeFalse)
+				_, ok = c.Get("other-dataset")
+				So(ok, ShouldBeFalse)
+				_, ok = c.Get("all-datasets")
+				So(ok, ShouldBeFalse)
+				_, ok = c.Get("topics")
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("When a value got before an invalidation is set after it", func() {
+			generation := c.Generation()
+			c.InvalidateDataset("TS009")
+			c.Set("key", "cpih01", []byte("stale value"), generation)
+
+			Convey("Then it is not stored", func() {
+				_, ok := c.Get("key")
+				So(ok, ShouldBeFalse)
+				So(c.Stats().Entries, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the TTL is 0", func() {
+			c := New(0)
+			c.Set("key", "cpih01", []byte("value"), c.Generation())
+
+			Convey("Then values are not cached", func() {
+				_, ok := c.Get("key")
+				So(ok, ShouldBeFalse)
+				So(c.Enabled(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Key is different for each user", t, func() {
+		So(Key("user-1", "collection", "/datasets"), ShouldNotEqual, Key("user-2", "collection", "/datasets"))
+		So(Key("user-1", "collection", "/datasets"), ShouldEqual, Key("user-1", "collection", "/datasets"))
+	})
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	cacheHeader      = "X-Cache"
+	dryRunQueryParam = "dry_run"
+)
+
+// DatasetIDFunc returns the ID of the dataset a request describes
+type DatasetIDFunc func(req *http.Request) string
+
+// RouteDatasetID returns the dataset ID from the route parameters
+func RouteDatasetID(req *http.Request) string {
+	return mux.Vars(req)["datasetID"]
+}
+
+// AllDatasetsID is used for routes whose responses hold more than one dataset
+func AllDatasetsID(req *http.Request) string {
+	return AllDatasets
+}
+
+// NoDatasetID is used for routes whose responses are not affected by dataset writes
+func NoDatasetID(req *http.Request) string {
+	return ""
+}
+
+// responseRecorder captures the status and body written by a handler while passing them through
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Handler serves successful responses from the cache, keyed by the user, collection ID, path and query
+func (c *Cache) Handler(datasetID DatasetIDFunc, h http.HandlerFunc) http.HandlerFunc {
+	if !c.Enabled() {
+		return h
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		accessToken, _ := dphandlers.GetFlorenceToken(ctx, req)
+		collectionID, _ := dprequest.GetCollectionID(req)
+		if accessToken == "" || collectionID == "" {
+			h(w, req)
+			return
+		}
+
+		key := Key(accessToken, collectionID, req.URL.RequestURI())
+		if b, ok := c.Get(key); ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(cacheHeader, "HIT")
+			if _, err := w.Write(b); err != nil {
+				log.Error(ctx, "failed to write cached response", err)
+			}
+			return
+		}
+
+		w.Header().Set(cacheHeader, "MISS")
+		generation := c.Generation()
+		rec := &responseRecorder{ResponseWriter: w}
+		h(rec, req)
+
+		if rec.status == http.StatusOK {
+			c.Set(key, datasetID(req), rec.body.Bytes(), generation)
+		}
+	}
+}
+
+// Invalidate removes the cached responses for the route's dataset once the handler has written to it successfully
+func (c *Cache) Invalidate(h http.HandlerFunc) http.HandlerFunc {
+	if !c.Enabled() {
+		return h
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w}
+		h(rec, req)
+
+		if wroteSuccessfully(rec, req) {
+			c.InvalidateDataset(RouteDatasetID(req))
+		}
+	}
+}
+
+// InvalidateAll removes the cached responses for every dataset once the handler has written successfully.
+// It is used for routes that write to more than one dataset
+func (c *Cache) InvalidateAll(h http.HandlerFunc) http.HandlerFunc {
+	if !c.Enabled() {
+		return h
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w}
+		h(rec, req)
+
+		if wroteSuccessfully(rec, req) {
+			c.InvalidateAllDatasets()
+		}
+	}
+}
+
+// wroteSuccessfully reports whether the handler completed a write, rather than failing or only planning it in a dry run
+func wroteSuccessfully(rec *responseRecorder, req *http.Request) bool {
+	if rec.status < http.StatusOK || rec.status >= http.StatusMultipleChoices {
+		return false
+	}
+
+	dryRun, err := strconv.ParseBool(req.URL.Query().Get(dryRunQueryParam))
+	return err != nil || !dryRun
+}
+
+// StatsHandler writes the cache hit and miss counts
+func (c *Cache) StatsHandler(w http.ResponseWriter, req *http.Request) {
+	b, err := json.Marshal(c.Stats())
+	if err != nil {
+		log.Error(req.Context(), "failed marshalling cache stats", err)
+		http.Error(w, "failed marshalling cache stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
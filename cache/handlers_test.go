@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitHandlers(t *testing.T) {
+	t.Parallel()
+
+	Convey("Given a cached route and a write route", t, func() {
+		c := New(time.Minute)
+
+		calls := 0
+		status := http.StatusOK
+		get := func(w http.ResponseWriter, req *http.Request) {
+			calls++
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"calls":%d}`, calls)
+		}
+		put := func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(status)
+		}
+
+		router := mux.NewRouter()
+		router.Path("/datasets/{datasetID}/editions").HandlerFunc(c.Handler(RouteDatasetID, get)).Methods(http.MethodGet)
+		router.Path("/datasets/{datasetID}/editions").HandlerFunc(c.Invalidate(put)).Methods(http.MethodPut)
+		router.Path("/collections/{collectionID}/bulk").HandlerFunc(c.InvalidateAll(put)).Methods(http.MethodPost)
+		router.Path("/cache/stats").HandlerFunc(c.StatsHandler)
+
+		doRequest := func(method, url, collectionID string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, url, nil)
+			req.Header.Set("X-Florence-Token", "testuser")
+			if collectionID != "" {
+				req.Header.Set("Collection-Id", collectionID)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		Convey("Then a repeated request is served from the cache", func() {
+			first := doRequest("GET", "/datasets/cpih01/editions", "collection")
+			second := doRequest("GET", "/datasets/cpih01/editions", "collection")
+
+			So(calls, ShouldEqual, 1)
+			So(first.Header().Get("X-Cache"), ShouldEqual, "MISS")
+			So(second.Header().Get("X-Cache"), ShouldEqual, "HIT")
+			So(second.Body.String(), ShouldEqual, first.Body.String())
+			So(second.Header().Get("Content-Type"), ShouldEqual, "application/json")
+		})
+
+		Convey("Then requests for another collection or query are not served from the cache", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "other-collection")
+			doRequest("GET", "/datasets/cpih01/editions?limit=1", "collection")
+
+			So(calls, ShouldEqual, 3)
+		})
+
+		Convey("Then requests without a collection ID are not cached", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "")
+			doRequest("GET", "/datasets/cpih01/editions", "")
+
+			So(calls, ShouldEqual, 2)
+		})
+
+		Convey("Then error responses are not cached", func() {
+			status = http.StatusNotFound
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+
+			So(calls, ShouldEqual, 2)
+		})
+
+		Convey("Then a successful write invalidates the dataset", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("PUT", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+
+			So(calls, ShouldEqual, 2)
+		})
+
+		Convey("Then a failed write does not invalidate the dataset", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			status = http.StatusInternalServerError
+			doRequest("PUT", "/datasets/cpih01/editions", "collection")
+			status = http.StatusOK
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+
+			So(calls, ShouldEqual, 1)
+		})
+
+		Convey("Then a dry run write does not invalidate the dataset", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("PUT", "/datasets/cpih01/editions?dry_run=true", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+
+			So(calls, ShouldEqual, 1)
+		})
+
+		Convey("Then a response got before a write is not cached after the write invalidates the dataset", func() {
+			staleGet := func(w http.ResponseWriter, req *http.Request) {
+				calls++
+				// the write completes while the stale response is being got
+				doRequest("PUT", "/datasets/cpih01/editions", "collection")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"calls":%d}`, calls)
+			}
+			router.Path("/datasets/{datasetID}/stale").HandlerFunc(c.Handler(RouteDatasetID, staleGet)).Methods(http.MethodGet)
+
+			doRequest("GET", "/datasets/cpih01/stale", "collection")
+			rec := doRequest("GET", "/datasets/cpih01/stale", "collection")
+
+			So(calls, ShouldEqual, 2)
+			So(rec.Header().Get("X-Cache"), ShouldEqual, "MISS")
+		})
+
+		Convey("Then a successful write to many datasets invalidates every dataset", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/TS009/editions", "collection")
+			doRequest("POST", "/collections/collection/bulk", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/TS009/editions", "collection")
+
+			So(calls, ShouldEqual, 4)
+		})
+
+		Convey("Then the hit and miss counts are exposed", func() {
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			doRequest("GET", "/datasets/cpih01/editions", "collection")
+			rec := doRequest("GET", "/cache/stats", "")
+
+			var stats Stats
+			So(json.Unmarshal(rec.Body.Bytes(), &stats), ShouldBeNil)
+			So(stats, ShouldResemble, Stats{Hits: 1, Misses: 1, Entries: 1})
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+// WithCriticality returns a checker that reports the state of the given checker. If the dependency is not critical
+// a critical state is reported as a warning, so that the dependency failing does not make the app critical
+func WithCriticality(name string, checker healthcheck.Checker, critical bool) healthcheck.Checker {
+	if critical {
+		return checker
+	}
+
+	return func(ctx context.Context, state *healthcheck.CheckState) error {
+		// the check is run against its own state so that the state being reported only changes once
+		checked := healthcheck.NewCheckState(name)
+		err := checker(ctx, checked)
+
+		status := checked.Status()
+		if status == "" {
+			return err
+		}
+		if status == healthcheck.StatusCritical {
+			status = healthcheck.StatusWarning
+		}
+
+		if updateErr := state.Update(status, checked.Message(), checked.StatusCode()); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitWithCriticality(t *testing.T) {
+	t.Parallel()
+
+	checkerWithStatus := func(status string, err error) healthcheck.Checker {
+		return func(ctx context.Context, state *healthcheck.CheckState) error {
+			if updateErr := state.Update(status, "Babbage is "+status, http.StatusInternalServerError); updateErr != nil {
+				return updateErr
+			}
+			return err
+		}
+	}
+
+	Convey("test WithCriticality", t, func() {
+		ctx := context.Background()
+		state := healthcheck.NewCheckState("Babbage")
+
+		Convey("a critical dependency reports its state unchanged", func() {
+			err := WithCriticality("Babbage", checkerWithStatus(healthcheck.StatusCritical, nil), true)(ctx, state)
+
+			So(err, ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusCritical)
+		})
+
+		Convey("a non-critical dependency", func() {
+			Convey("reports a critical state as a warning", func() {
+				checkErr := errors.New("babbage unavailable")
+				err := WithCriticality("Babbage", checkerWithStatus(healthcheck.StatusCritical, checkErr), false)(ctx, state)
+
+				So(err, ShouldEqual, checkErr)
+				So(state.Status(), ShouldEqual, healthcheck.StatusWarning)
+				So(state.Message(), ShouldEqual, "Babbage is CRITICAL")
+				So(state.StatusCode(), ShouldEqual, http.StatusInternalServerError)
+				So(state.LastFailure(), ShouldNotBeNil)
+			})
+
+			Convey("reports other states unchanged", func() {
+				err := WithCriticality("Babbage", checkerWithStatus(healthcheck.StatusOK, nil), false)(ctx, state)
+
+				So(err, ShouldBeNil)
+				So(state.Status(), ShouldEqual, healthcheck.StatusOK)
+				So(state.LastSuccess(), ShouldNotBeNil)
+			})
+
+			Convey("does not update the state if the check does not", func() {
+				checkErr := errors.New("check failed")
+				err := WithCriticality("Babbage", func(ctx context.Context, state *healthcheck.CheckState) error {
+					return checkErr
+				}, false)(ctx, state)
+
+				So(err, ShouldEqual, checkErr)
+				So(state.Status(), ShouldBeEmpty)
+				So(state.LastChecked(), ShouldBeNil)
+			})
+		})
+	})
+}
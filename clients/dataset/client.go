@@ -0,0 +1,176 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-api-clients-go/v2/headers"
+	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/pkg/errors"
+)
+
+const service = "dataset-api"
+
+// Client represents a dataset API client. It embeds the dp-api-clients-go dataset client
+// and adds the calls that the controller needs but the shared client does not provide
+type Client struct {
+	*datasetclient.Client
+	hcCli *healthcheck.Client
+}
+
+// NewWithHealthClient creates a new instance of Client, reusing the URL and Clienter from the provided health check client
+func NewWithHealthClient(hcCli *healthcheck.Client) *Client {
+	return &Client{
+		Client: datasetclient.NewWithHealthClient(hcCli),
+		hcCli:  healthcheck.NewClientWithClienter(service, hcCli.URL, hcCli.Client),
+	}
+}
+
+// CreateDataset creates a new dataset with the given ID and returns the dataset as stored by the dataset API
+func (c *Client) CreateDataset(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) (m datasetclient.Dataset, err error) {
+	uri := fmt.Sprintf("%s/datasets/%s", c.hcCli.URL, datasetID)
+
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return m, errors.Wrap(err, "error while attempting to marshall dataset")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(payload))
+	if err != nil {
+		return m, err
+	}
+	if len(collectionID) > 0 {
+		req.Header.Add(dprequest.CollectionIDHeaderKey, collectionID)
+	}
+	dprequest.AddFlorenceHeader(req, userAuthToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+
+	resp, err := c.hcCli.Client.Do(ctx, req)
+	if err != nil {
+		return m, errors.Wrap(err, "http client returned error while attempting to make request")
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if resp.StatusCode != http.StatusCreated {
+		return m, datasetclient.NewDatasetAPIResponse(resp, uri)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+// DeleteDataset deletes a dataset that has not been published
+func (c *Client) DeleteDataset(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) error {
+	uri := fmt.Sprintf("%s/datasets/%s", c.hcCli.URL, datasetID)
+
+	req, err := http.NewRequest(http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+	if len(collectionID) > 0 {
+		req.Header.Add(dprequest.CollectionIDHeaderKey, collectionID)
+	}
+	dprequest.AddFlorenceHeader(req, userAuthToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+
+	resp, err := c.hcCli.Client.Do(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "http client returned error while attempting to make request")
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if resp.StatusCode != http.StatusNoContent {
+		return datasetclient.NewDatasetAPIResponse(resp, uri)
+	}
+	return nil
+}
+
+// PutVersion updates a version, guarded by the given ETag if one is set, and returns the new ETag. A version shares its
+// ETag with its instance, so the returned value is needed for any later write to either. It replaces the shared
+// client's call so that a failed request returns the dataset API status code rather than only describing it in the
+// error message
+func (c *Client) PutVersion(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (eTag string, err error) {
+	uri := fmt.Sprintf("%s/datasets/%s/editions/%s/versions/%s", c.hcCli.URL, datasetID, edition, version)
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "error while attempting to marshall version")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, uri, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	if len(collectionID) > 0 {
+		req.Header.Add(dprequest.CollectionIDHeaderKey, collectionID)
+	}
+	if err = headers.SetIfMatch(req, ifMatch); err != nil {
+		return "", err
+	}
+	dprequest.AddFlorenceHeader(req, userAuthToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+
+	resp, err := c.hcCli.Client.Do(ctx, req)
+	if err != nil {
+		return "", errors.Wrap(err, "http client returned error while attempting to make request")
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", datasetclient.NewDatasetAPIResponse(resp, uri)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// PutMetadata updates the editable metadata of a dataset and version in a single call, guarded by the version ETag. It
+// replaces the shared client's call so that the new version ETag is returned
+func (c *Client) PutMetadata(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, metadata datasetclient.EditableMetadata, versionEtag string) (eTag string, err error) {
+	uri := fmt.Sprintf("%s/datasets/%s/editions/%s/versions/%s/metadata", c.hcCli.URL, datasetID, edition, version)
+
+	payload, err := json.Marshal(metadata)
+	if err != nil {
+		return "", errors.Wrap(err, "error while attempting to marshall metadata")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, uri, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	if len(collectionID) > 0 {
+		req.Header.Add(dprequest.CollectionIDHeaderKey, collectionID)
+	}
+	if err = headers.SetIfMatch(req, versionEtag); err != nil {
+		return "", err
+	}
+	dprequest.AddFlorenceHeader(req, userAuthToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+
+	resp, err := c.hcCli.Client.Do(ctx, req)
+	if err != nil {
+		return "", errors.Wrap(err, "http client returned error while attempting to make request")
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", datasetclient.NewDatasetAPIResponse(resp, uri)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// closeResponseBody closes the response body and logs an error containing the context if unsuccessful
+func closeResponseBody(ctx context.Context, resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Error(ctx, "error closing http response body", err)
+	}
+}
@@ -0,0 +1,85 @@
+package dataset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPutVersion(t *testing.T) {
+	t.Parallel()
+
+	Convey("test PutVersion", t, func() {
+		var received *http.Request
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			w.Header().Set("ETag", "new-etag")
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		c := NewWithHealthClient(healthcheck.NewClient(service, server.URL))
+
+		Convey("puts the version guarded by the etag and returns the new etag", func() {
+			eTag, err := c.PutVersion(context.Background(), "user-token", "service-token", "collection", "cpih01", "time-series", "1", datasetclient.Version{ID: "1"}, "version-etag")
+
+			So(err, ShouldBeNil)
+			So(eTag, ShouldEqual, "new-etag")
+			So(received.Method, ShouldEqual, http.MethodPut)
+			So(received.URL.Path, ShouldEqual, "/datasets/cpih01/editions/time-series/versions/1")
+			So(received.Header.Get("Collection-Id"), ShouldEqual, "collection")
+			So(received.Header.Get("X-Florence-Token"), ShouldEqual, "user-token")
+			So(received.Header.Get("If-Match"), ShouldEqual, "version-etag")
+		})
+
+		Convey("returns an error with the dataset API status code", func() {
+			status = http.StatusConflict
+			_, err := c.PutVersion(context.Background(), "user-token", "service-token", "collection", "cpih01", "time-series", "1", datasetclient.Version{ID: "1"}, "version-etag")
+
+			So(err, ShouldNotBeNil)
+			So(err.(*datasetclient.ErrInvalidDatasetAPIResponse).Code(), ShouldEqual, http.StatusConflict)
+		})
+	})
+}
+
+func TestUnitPutMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("test PutMetadata", t, func() {
+		var received *http.Request
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			w.Header().Set("ETag", "new-etag")
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		c := NewWithHealthClient(healthcheck.NewClient(service, server.URL))
+
+		Convey("puts the metadata guarded by the version etag and returns the new etag", func() {
+			eTag, err := c.PutMetadata(context.Background(), "user-token", "service-token", "collection", "cpih01", "time-series", "1", datasetclient.EditableMetadata{Title: "title"}, "version-etag")
+
+			So(err, ShouldBeNil)
+			So(eTag, ShouldEqual, "new-etag")
+			So(received.Method, ShouldEqual, http.MethodPut)
+			So(received.URL.Path, ShouldEqual, "/datasets/cpih01/editions/time-series/versions/1/metadata")
+			So(received.Header.Get("If-Match"), ShouldEqual, "version-etag")
+		})
+
+		Convey("returns an error with the dataset API status code", func() {
+			status = http.StatusConflict
+			_, err := c.PutMetadata(context.Background(), "user-token", "service-token", "collection", "cpih01", "time-series", "1", datasetclient.EditableMetadata{}, "version-etag")
+
+			So(err, ShouldNotBeNil)
+			So(err.(*datasetclient.ErrInvalidDatasetAPIResponse).Code(), ShouldEqual, http.StatusConflict)
+		})
+	})
+}
@@ -0,0 +1,104 @@
+package topics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling babbage while the circuit breaker is open
+type ErrCircuitOpen struct {
+	retryAt time.Time
+}
+
+// Error should be called by the user to print out the stringified version of the error
+func (e ErrCircuitOpen) Error() string {
+	return "babbage circuit breaker is open: not calling babbage until " + e.retryAt.UTC().Format(time.RFC3339)
+}
+
+// Code returns the status code that best describes babbage being unavailable
+func (e ErrCircuitOpen) Code() int {
+	return http.StatusServiceUnavailable
+}
+
+// breaker is a circuit breaker that opens after a number of consecutive failed calls to babbage. While open, calls
+// fail fast until the open timeout has passed, after which a single call is let through to test whether babbage has
+// recovered
+type breaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	failures int
+	openedAt time.Time
+	open     bool
+	probing  bool
+}
+
+func newBreaker(failureThreshold int, openTimeout time.Duration) *breaker {
+	return &breaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// allow returns an error if a call to babbage should not be made. A nil breaker, or one with no failure threshold,
+// always allows calls
+func (b *breaker) allow() error {
+	if b == nil || b.failureThreshold < 1 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+
+	retryAt := b.openedAt.Add(b.openTimeout)
+	if b.probing || b.now().Before(retryAt) {
+		return ErrCircuitOpen{retryAt: retryAt}
+	}
+	b.probing = true
+	return nil
+}
+
+// record records the outcome of an allowed call, opening the breaker once the failure threshold is reached or if a
+// test call fails, and closing it after any successful call
+func (b *breaker) record(failed bool) {
+	if b == nil || b.failureThreshold < 1 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.open = false
+		return
+	}
+
+	b.failures++
+	if b.open || b.failures >= b.failureThreshold {
+		b.open = true
+		b.openedAt = b.now()
+	}
+}
+
+// release is called instead of record when an allowed call ends without an outcome, such as when the caller's
+// request is cancelled, so that another test call can be made
+func (b *breaker) release() {
+	if b == nil || b.failureThreshold < 1 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
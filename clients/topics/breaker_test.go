@@ -0,0 +1,74 @@
+package topics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitBreaker(t *testing.T) {
+	t.Parallel()
+
+	Convey("test breaker", t, func() {
+		now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		b := newBreaker(2, time.Minute)
+		b.now = func() time.Time { return now }
+
+		Convey("allows calls until the failure threshold is reached", func() {
+			So(b.allow(), ShouldBeNil)
+			b.record(true)
+			So(b.allow(), ShouldBeNil)
+			b.record(true)
+
+			err := b.allow()
+			So(err, ShouldResemble, ErrCircuitOpen{retryAt: now.Add(time.Minute)})
+			So(err.(ErrCircuitOpen).Code(), ShouldEqual, http.StatusServiceUnavailable)
+		})
+
+		Convey("only counts consecutive failures", func() {
+			b.record(true)
+			b.record(false)
+			b.record(true)
+
+			So(b.allow(), ShouldBeNil)
+		})
+
+		Convey("once open", func() {
+			b.record(true)
+			b.record(true)
+
+			Convey("lets a single test call through after the open timeout", func() {
+				now = now.Add(time.Minute)
+
+				So(b.allow(), ShouldBeNil)
+				So(b.allow(), ShouldHaveSameTypeAs, ErrCircuitOpen{})
+
+				Convey("closing if it succeeds", func() {
+					b.record(false)
+					So(b.allow(), ShouldBeNil)
+					So(b.allow(), ShouldBeNil)
+				})
+
+				Convey("opening again if it fails", func() {
+					b.record(true)
+					So(b.allow(), ShouldResemble, ErrCircuitOpen{retryAt: now.Add(time.Minute)})
+				})
+
+				Convey("letting another test call through if it is released", func() {
+					b.release()
+					So(b.allow(), ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("always allows calls if it has no failure threshold", func() {
+			b := newBreaker(0, time.Minute)
+			b.record(true)
+			b.record(true)
+
+			So(b.allow(), ShouldBeNil)
+		})
+	})
+}
@@ -3,13 +3,20 @@ package topics
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
 
 	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 	dphttp "github.com/ONSdigital/dp-net/http"
+	dprequest "github.com/ONSdigital/dp-net/request"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
@@ -17,8 +24,40 @@ const service = "Babbage"
 
 // Client represents a babbage client
 type Client struct {
-	cli dphttp.Clienter
-	url string
+	cli     dphttp.Clienter
+	url     string
+	cfg     Config
+	breaker *breaker
+
+	mu          sync.RWMutex
+	staleTopics []TaxonomyNode
+}
+
+// Config sets how the client retries failed requests and when it stops calling babbage
+type Config struct {
+	// MaxRetries is the number of times a failed request is retried
+	MaxRetries int
+	// RetryBackoff is the backoff before the first retry, doubling for each retry after it. Up to half of each
+	// backoff is random jitter
+	RetryBackoff time.Duration
+	// MaxRetryBackoff limits the backoff between retries
+	MaxRetryBackoff time.Duration
+	// BreakerFailureThreshold is the number of consecutive failed requests that opens the circuit breaker. Zero
+	// disables the circuit breaker
+	BreakerFailureThreshold int
+	// BreakerOpenTimeout is how long the circuit breaker stays open before babbage is tried again
+	BreakerOpenTimeout time.Duration
+}
+
+// DefaultConfig returns the config used by New
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries:              3,
+		RetryBackoff:            100 * time.Millisecond,
+		MaxRetryBackoff:         2 * time.Second,
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      30 * time.Second,
+	}
 }
 
 // ErrInvalidBabbageResponse is returned when the babbage service does not respond with a status 200
@@ -36,13 +75,23 @@ func (e ErrInvalidBabbageResponse) Code() int {
 	return e.responseCode
 }
 
-// New creates a new instance of Client with a given babbage url
+// New creates a new instance of Client with a given babbage url and the default config
 func New(babbageURL string) *Client {
+	return NewWithConfig(babbageURL, DefaultConfig())
+}
+
+// NewWithConfig creates a new instance of Client with a given babbage url and config
+func NewWithConfig(babbageURL string, cfg Config) *Client {
 	hcClient := healthcheck.NewClient(service, babbageURL)
 
+	// requests are retried by the client so that each request counts once towards opening the circuit breaker
+	hcClient.Client.SetMaxRetries(0)
+
 	return &Client{
-		cli: hcClient.Client,
-		url: babbageURL,
+		cli:     hcClient.Client,
+		url:     babbageURL,
+		cfg:     cfg,
+		breaker: newBreaker(cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout),
 	}
 }
 
@@ -57,34 +106,181 @@ func (c *Client) Checker(ctx context.Context, check *health.CheckState) error {
 	return hcClient.Checker(ctx, check)
 }
 
-func (c *Client) GetTopics(ctx context.Context, userAccessToken string) (result TopicsResult, err error) {
-	uri := fmt.Sprintf("%s/allmethodologies/data", c.url)
-	resp, err := c.get(ctx, uri)
+// GetTopicTree gets babbage's navigation data and assembles it into the taxonomy of topics and their subtopics. If
+// babbage is unavailable the last taxonomy successfully got is returned instead
+func (c *Client) GetTopicTree(ctx context.Context, userAccessToken, serviceAuthToken string) ([]TaxonomyNode, error) {
+	tree, err := c.getTopicTree(ctx, userAccessToken, serviceAuthToken)
+	if err == nil {
+		c.mu.Lock()
+		c.staleTopics = tree
+		c.mu.Unlock()
+		return tree, nil
+	}
+
+	if !isUnavailable(ctx, err) {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	stale := c.staleTopics
+	c.mu.RUnlock()
+	if stale == nil {
+		return nil, err
+	}
+
+	log.Warn(ctx, "babbage unavailable: returning stale topics", log.FormatErrors([]error{err}))
+	return stale, nil
+}
+
+func (c *Client) getTopicTree(ctx context.Context, userAccessToken, serviceAuthToken string) ([]TaxonomyNode, error) {
+	uri := fmt.Sprintf("%s/navigationdata", c.url)
+	resp, err := c.get(ctx, userAccessToken, serviceAuthToken, uri)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 	defer closeResponseBody(ctx, resp)
 
 	if resp.StatusCode != http.StatusOK {
-		return result, ErrInvalidBabbageResponse{resp.StatusCode}
+		return nil, ErrInvalidBabbageResponse{resp.StatusCode}
 	}
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return
+		return nil, err
+	}
+
+	var items []NavigationItem
+	if err = json.Unmarshal(b, &items); err != nil {
+		return nil, err
 	}
-	err = json.Unmarshal(b, &result)
-	return
+	return taxonomy(items), nil
 }
 
-func (c *Client) get(ctx context.Context, uri string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
+// taxonomy converts navigation items to taxonomy nodes, skipping items without a URI as they cannot be referenced
+func taxonomy(items []NavigationItem) []TaxonomyNode {
+	nodes := []TaxonomyNode{}
+	for _, item := range items {
+		uri := strings.TrimSuffix(item.URI, "/")
+		if uri == "" {
+			continue
+		}
+		nodes = append(nodes, TaxonomyNode{
+			ID:       path.Base(uri),
+			Title:    item.Description.Title,
+			URI:      uri,
+			Type:     item.Type,
+			Children: taxonomy(item.Children),
+		})
+	}
+	return nodes
+}
+
+// get makes a GET request to babbage, retrying it if babbage fails or cannot be reached. Requests are not made while
+// the circuit breaker is open
+func (c *Client) get(ctx context.Context, userAccessToken, serviceAuthToken, uri string) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.getWithRetries(ctx, userAccessToken, serviceAuthToken, uri)
+	if ctx.Err() != nil {
+		c.breaker.release()
+		return resp, err
+	}
+
+	c.breaker.record(isRetryable(resp, err))
+	return resp, err
+}
+
+func (c *Client) getWithRetries(ctx context.Context, userAccessToken, serviceAuthToken, uri string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, userAccessToken, serviceAuthToken, uri)
+		if attempt >= c.cfg.MaxRetries || !isRetryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			err = ErrInvalidBabbageResponse{resp.StatusCode}
+			closeResponseBody(ctx, resp)
+		}
+		log.Warn(ctx, "babbage request failed: retrying", log.FormatErrors([]error{err}), log.Data{"uri": uri, "attempt": attempt + 1})
+
+		if err = sleep(ctx, c.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do makes a single GET request to babbage with the user and service auth headers set. The request ID is taken from
+// the context and added to the request by the dp-net client
+func (c *Client) do(ctx context.Context, userAccessToken, serviceAuthToken, uri string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return nil, err
 	}
+
+	dprequest.AddFlorenceHeader(req, userAccessToken)
+	dprequest.AddServiceTokenHeader(req, serviceAuthToken)
+
 	return c.cli.Do(ctx, req)
 }
 
+// backoff returns how long to wait before a retry, doubling RetryBackoff for each attempt up to MaxRetryBackoff with
+// up to half of it as random jitter so that instances do not retry together
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := c.cfg.RetryBackoff
+	for i := 0; i < attempt; i++ {
+		backoff *= 2
+	}
+	if c.cfg.MaxRetryBackoff > 0 && (backoff > c.cfg.MaxRetryBackoff || backoff < c.cfg.RetryBackoff) {
+		backoff = c.cfg.MaxRetryBackoff
+	}
+
+	half := int64(backoff / 2)
+	if half <= 0 {
+		return backoff
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// sleep waits for the duration, returning early with the context's error if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryable reports whether a request failed because babbage could not be reached or had a server error
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// isUnavailable reports whether an error returned by the client means babbage is unavailable, rather than that the
+// request was cancelled or rejected
+func isUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var invalidResponse ErrInvalidBabbageResponse
+	if errors.As(err, &invalidResponse) {
+		return isRetryable(&http.Response{StatusCode: invalidResponse.Code()}, nil)
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr)
+}
+
 // closeResponseBody closes the response body and logs an error containing the context if unsuccessful
 func closeResponseBody(ctx context.Context, resp *http.Response) {
 	if err := resp.Body.Close(); err != nil {
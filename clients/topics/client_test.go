@@ -0,0 +1,216 @@
+package topics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dprequest "github.com/ONSdigital/dp-net/request"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetTopicTree(t *testing.T) {
+	t.Parallel()
+
+	navigationData := `[
+		{"uri":"/economy","type":"taxonomy_landing_page","description":{"title":"Economy"},"children":[
+			{"uri":"/economy/inflationandpriceindices/","type":"product_page","description":{"title":"Inflation and price indices"}},
+			{"uri":"","type":"product_page","description":{"title":"No URI"}}
+		]}
+	]`
+
+	Convey("test GetTopicTree", t, func() {
+		var received *http.Request
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			w.WriteHeader(status)
+			w.Write([]byte(navigationData))
+		}))
+		defer server.Close()
+
+		c := New(server.URL)
+		ctx := dprequest.WithRequestId(context.Background(), "request-id")
+
+		Convey("assembles the taxonomy from the navigation data", func() {
+			tree, err := c.GetTopicTree(ctx, "user-token", "service-token")
+
+			So(err, ShouldBeNil)
+			So(received.URL.Path, ShouldEqual, "/navigationdata")
+			So(tree, ShouldResemble, []TaxonomyNode{
+				{
+					ID:    "economy",
+					Title: "Economy",
+					URI:   "/economy",
+					Type:  "taxonomy_landing_page",
+					Children: []TaxonomyNode{
+						{ID: "inflationandpriceindices", Title: "Inflation and price indices", URI: "/economy/inflationandpriceindices", Type: "product_page", Children: []TaxonomyNode{}},
+					},
+				},
+			})
+		})
+
+		Convey("forwards the user and service tokens and the request ID", func() {
+			_, err := c.GetTopicTree(ctx, "user-token", "service-token")
+
+			So(err, ShouldBeNil)
+			So(received.Header.Get(dprequest.FlorenceHeaderKey), ShouldEqual, "user-token")
+			So(received.Header.Get(dprequest.AuthHeaderKey), ShouldEqual, "Bearer service-token")
+			So(strings.HasPrefix(received.Header.Get(dprequest.RequestHeaderKey), "request-id,"), ShouldBeTrue)
+		})
+
+		Convey("does not set auth headers for empty tokens", func() {
+			_, err := c.GetTopicTree(ctx, "", "")
+
+			So(err, ShouldBeNil)
+			So(received.Header.Values(dprequest.FlorenceHeaderKey), ShouldBeEmpty)
+			So(received.Header.Values(dprequest.AuthHeaderKey), ShouldBeEmpty)
+		})
+
+		Convey("returns an error for a non-200 response", func() {
+			status = http.StatusNotFound
+			_, err := c.GetTopicTree(ctx, "user-token", "")
+
+			So(err, ShouldResemble, ErrInvalidBabbageResponse{http.StatusNotFound})
+			So(err.(ErrInvalidBabbageResponse).Code(), ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func TestUnitResilience(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		MaxRetries:              2,
+		RetryBackoff:            time.Millisecond,
+		MaxRetryBackoff:         2 * time.Millisecond,
+		BreakerFailureThreshold: 2,
+		BreakerOpenTimeout:      time.Minute,
+	}
+
+	Convey("test the client's resilience to babbage failing", t, func() {
+		var calls int32
+		var statuses []int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			call := int(atomic.AddInt32(&calls, 1)) - 1
+			status := http.StatusOK
+			if call < len(statuses) {
+				status = statuses[call]
+			}
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				w.Write([]byte(`[{"uri":"/economy","description":{"title":"Economy"}}]`))
+			}
+		}))
+		defer server.Close()
+
+		c := NewWithConfig(server.URL, cfg)
+		ctx := context.Background()
+
+		Convey("retries server errors until babbage responds", func() {
+			statuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable}
+
+			tree, err := c.GetTopicTree(ctx, "", "")
+
+			So(err, ShouldBeNil)
+			So(tree, ShouldHaveLength, 1)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 3)
+		})
+
+		Convey("does not retry client errors", func() {
+			statuses = []int{http.StatusUnauthorized}
+
+			_, err := c.GetTopicTree(ctx, "", "")
+
+			So(err, ShouldResemble, ErrInvalidBabbageResponse{http.StatusUnauthorized})
+			So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+		})
+
+		Convey("returns the last response once the retries are used up", func() {
+			statuses = []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}
+
+			_, err := c.GetTopicTree(ctx, "", "")
+
+			So(err, ShouldResemble, ErrInvalidBabbageResponse{http.StatusInternalServerError})
+			So(atomic.LoadInt32(&calls), ShouldEqual, 3)
+		})
+
+		Convey("fails fast without calling babbage once the circuit breaker opens", func() {
+			statuses = []int{500, 500, 500, 500, 500, 500}
+
+			_, err := c.GetTopicTree(ctx, "", "")
+			So(err, ShouldNotBeNil)
+			_, err = c.GetTopicTree(ctx, "", "")
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&calls), ShouldEqual, 6)
+
+			_, err = c.GetTopicTree(ctx, "", "")
+			So(err, ShouldHaveSameTypeAs, ErrCircuitOpen{})
+			So(atomic.LoadInt32(&calls), ShouldEqual, 6)
+		})
+
+		Convey("returns the last topics while babbage is unavailable", func() {
+			statuses = []int{http.StatusOK, 500, 500, 500}
+
+			tree, err := c.GetTopicTree(ctx, "", "")
+			So(err, ShouldBeNil)
+
+			stale, err := c.GetTopicTree(ctx, "", "")
+			So(err, ShouldBeNil)
+			So(stale, ShouldResemble, tree)
+
+			Convey("including when the circuit breaker is open", func() {
+				statuses = append(statuses, 500, 500, 500)
+				_, err = c.GetTopicTree(ctx, "", "")
+				So(err, ShouldBeNil)
+
+				stale, err = c.GetTopicTree(ctx, "", "")
+				So(err, ShouldBeNil)
+				So(stale, ShouldResemble, tree)
+				So(atomic.LoadInt32(&calls), ShouldEqual, 7)
+			})
+		})
+
+		Convey("does not return the last topics for a client error", func() {
+			statuses = []int{http.StatusOK, http.StatusUnauthorized}
+
+			_, err := c.GetTopicTree(ctx, "", "")
+			So(err, ShouldBeNil)
+
+			_, err = c.GetTopicTree(ctx, "", "")
+			So(err, ShouldResemble, ErrInvalidBabbageResponse{http.StatusUnauthorized})
+		})
+
+		Convey("stops retrying when the context is cancelled", func() {
+			statuses = []int{500, 500, 500}
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := c.GetTopicTree(ctx, "", "")
+
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&calls), ShouldBeLessThanOrEqualTo, 1)
+			So(c.breaker.allow(), ShouldBeNil)
+		})
+	})
+}
+
+func TestUnitBackoff(t *testing.T) {
+	t.Parallel()
+
+	Convey("backoff doubles for each attempt, with jitter, up to the maximum", t, func() {
+		c := NewWithConfig("http://localhost", Config{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: 300 * time.Millisecond})
+
+		for i := 0; i < 20; i++ {
+			So(c.backoff(0), ShouldBeBetweenOrEqual, 50*time.Millisecond, 100*time.Millisecond)
+			So(c.backoff(1), ShouldBeBetweenOrEqual, 100*time.Millisecond, 200*time.Millisecond)
+			So(c.backoff(5), ShouldBeBetweenOrEqual, 150*time.Millisecond, 300*time.Millisecond)
+			So(c.backoff(100), ShouldBeBetweenOrEqual, 150*time.Millisecond, 300*time.Millisecond)
+		}
+	})
+}
@@ -1,19 +1,22 @@
 package topics
 
-type TopicsResult struct {
-	Topics Topic `json:"topics"`
-}
-
-type Topic struct {
-	Results []Result `json:"results"`
+type Description struct {
+	Title string `json:"title"`
 }
 
-type Result struct {
-	Description Description `json:"description"`
-	URI         string      `json:"uri"`
-	Type        string      `json:"type"`
+// NavigationItem is a page in babbage's navigation data, the taxonomy of topics with their subtopics as children
+type NavigationItem struct {
+	Description Description      `json:"description"`
+	URI         string           `json:"uri"`
+	Type        string           `json:"type"`
+	Children    []NavigationItem `json:"children"`
 }
 
-type Description struct {
-	Title string `json:"title"`
+// TaxonomyNode is a topic in the taxonomy, identified by the last segment of its URI
+type TaxonomyNode struct {
+	ID       string
+	Title    string
+	URI      string
+	Type     string
+	Children []TaxonomyNode
 }
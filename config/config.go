@@ -12,12 +12,24 @@ var cfg *Config
 type Config struct {
 	BindAddr                  string        `envconfig:"BIND_ADDR"`
 	APIRouterURL              string        `envconfig:"API_ROUTER_URL"`
+	DatasetAPIURL             string        `envconfig:"DATASET_API_URL"`
+	ZebedeeURL                string        `envconfig:"ZEBEDEE_URL"`
 	GracefulShutdownTimeout   time.Duration `envconfig:"GRACEFUL_SHUTDOWN_TIMEOUT"`
 	HealthCheckInterval       time.Duration `envconfig:"HEALTHCHECK_INTERVAL"`
 	HealthCheckCritialTimeout time.Duration `envconfig:"HEALTHCHECK_CRITICAL_TIMEOUT"`
+	DatasetAPICritical        bool          `envconfig:"DATASET_API_HEALTHCHECK_CRITICAL"`
+	ZebedeeCritical           bool          `envconfig:"ZEBEDEE_HEALTHCHECK_CRITICAL"`
+	BabbageCritical           bool          `envconfig:"BABBAGE_HEALTHCHECK_CRITICAL"`
 	BabbageURL                string        `envconfig:"BABBAGE_URL"`
+	BabbageMaxRetries         int           `envconfig:"BABBAGE_MAX_RETRIES"`
+	BabbageRetryBackoff       time.Duration `envconfig:"BABBAGE_RETRY_BACKOFF"`
+	BabbageMaxRetryBackoff    time.Duration `envconfig:"BABBAGE_MAX_RETRY_BACKOFF"`
+	BabbageBreakerThreshold   int           `envconfig:"BABBAGE_BREAKER_FAILURE_THRESHOLD"`
+	BabbageBreakerOpenTimeout time.Duration `envconfig:"BABBAGE_BREAKER_OPEN_TIMEOUT"`
 	DatasetsBatchSize         int           `envconfig:"DATASET_BATCH_SIZE"`
 	DatasetsBatchWorkers      int           `envconfig:"DATASET_BATCH_WORKERS"`
+	CacheTTL                  time.Duration `envconfig:"CACHE_TTL"`
+	ServiceAuthToken          string        `envconfig:"SERVICE_AUTH_TOKEN" json:"-"`
 }
 
 // Get retrieves the config from the environment for florence
@@ -29,12 +41,23 @@ func Get() (*Config, error) {
 	cfg = &Config{
 		BindAddr:                  ":24000",
 		APIRouterURL:              "http://localhost:23200/v1",
+		DatasetAPIURL:             "http://localhost:22000",
+		ZebedeeURL:                "http://localhost:8082",
 		GracefulShutdownTimeout:   5 * time.Second,
 		HealthCheckInterval:       30 * time.Second,
 		HealthCheckCritialTimeout: 90 * time.Second,
+		DatasetAPICritical:        true,
+		ZebedeeCritical:           true,
+		BabbageCritical:           false,
 		BabbageURL:                "http://localhost:8080",
+		BabbageMaxRetries:         3,
+		BabbageRetryBackoff:       100 * time.Millisecond,
+		BabbageMaxRetryBackoff:    2 * time.Second,
+		BabbageBreakerThreshold:   5,
+		BabbageBreakerOpenTimeout: 30 * time.Second,
 		DatasetsBatchSize:         100,
 		DatasetsBatchWorkers:      10,
+		CacheTTL:                  30 * time.Second,
 	}
 
 	return cfg, envconfig.Process("", cfg)
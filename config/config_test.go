@@ -20,12 +20,24 @@ func TestSpec(t *testing.T) {
 			Convey("The values should be set to the expected defaults", func() {
 				So(cfg.BindAddr, ShouldEqual, ":24000")
 				So(cfg.APIRouterURL, ShouldEqual, "http://localhost:23200/v1")
+				So(cfg.DatasetAPIURL, ShouldEqual, "http://localhost:22000")
+				So(cfg.ZebedeeURL, ShouldEqual, "http://localhost:8082")
 				So(cfg.GracefulShutdownTimeout, ShouldEqual, 5*time.Second)
 				So(cfg.HealthCheckInterval, ShouldEqual, 30*time.Second)
 				So(cfg.HealthCheckCritialTimeout, ShouldEqual, 90*time.Second)
+				So(cfg.DatasetAPICritical, ShouldBeTrue)
+				So(cfg.ZebedeeCritical, ShouldBeTrue)
+				So(cfg.BabbageCritical, ShouldBeFalse)
 				So(cfg.BabbageURL, ShouldEqual, "http://localhost:8080")
+				So(cfg.BabbageMaxRetries, ShouldEqual, 3)
+				So(cfg.BabbageRetryBackoff, ShouldEqual, 100*time.Millisecond)
+				So(cfg.BabbageMaxRetryBackoff, ShouldEqual, 2*time.Second)
+				So(cfg.BabbageBreakerThreshold, ShouldEqual, 5)
+				So(cfg.BabbageBreakerOpenTimeout, ShouldEqual, 30*time.Second)
 				So(cfg.DatasetsBatchSize, ShouldEqual, 100)
 				So(cfg.DatasetsBatchWorkers, ShouldEqual, 10)
+				So(cfg.CacheTTL, ShouldEqual, 30*time.Second)
+				So(cfg.ServiceAuthToken, ShouldBeEmpty)
 			})
 		})
 	})
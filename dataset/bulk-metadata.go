@@ -0,0 +1,224 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// maxBulkMetadataTargets limits the number of versions a single bulk metadata request can update
+const maxBulkMetadataTargets = 100
+
+// PostBulkMetadata applies the same editable metadata patch to many dataset versions in a collection
+func PostBulkMetadata(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string, maxWorkers int) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postBulkMetadata(w, r, dc, zc, accessToken, serviceAuthToken, maxWorkers)
+	})
+}
+
+func postBulkMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken string, maxWorkers int) {
+	ctx := req.Context()
+
+	// the collection being updated is taken from the path rather than the Collection-Id header
+	collectionID := mux.Vars(req)["collectionID"]
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	logInfo := map[string]interface{}{
+		"collectionID": collectionID,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "postBulkMetadata endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var body model.BulkMetadata
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "postBulkMetadata endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if err = validateBulkMetadataRequest(body); err != nil {
+		log.Error(ctx, "postBulkMetadata endpoint: invalid body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	if fieldErrors := validateBulkMetadataFields(body); len(fieldErrors) > 0 {
+		writeValidationError(w, req, fieldErrors, logInfo)
+		return
+	}
+	logInfo["targets"] = len(body.Targets)
+
+	// the collection is got once so that each dataset keeps its own state in it when its metadata is saved
+	c, err := getCollectionDetails(ctx, zc, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, "failed Get collection details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, zebedeeService, "failed to get collection details", err)
+		return
+	}
+
+	log.Info(ctx, "calling bulk metadata update", log.Data(logInfo))
+
+	results := applyBulkMetadata(ctx, req, dc, zc, userAccessToken, serviceAuthToken, collectionID, c, body, maxWorkers)
+
+	report := model.BulkMetadataReport{Results: results}
+	for _, result := range results {
+		if result.Error != nil {
+			report.Failed++
+			continue
+		}
+		report.Succeeded++
+	}
+	logInfo["succeeded"] = report.Succeeded
+	logInfo["failed"] = report.Failed
+
+	response, err := json.Marshal(report)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "bulk metadata update: request complete", log.Data(logInfo))
+}
+
+// validateBulkMetadataRequest checks that there is a patch and an acceptable number of targets
+func validateBulkMetadataRequest(body model.BulkMetadata) error {
+	if len(body.Patch) == 0 {
+		return errors.New("patch is required")
+	}
+	if len(body.Targets) == 0 {
+		return errors.New("targets are required")
+	}
+	if len(body.Targets) > maxBulkMetadataTargets {
+		return fmt.Errorf("too many targets: the maximum is %d", maxBulkMetadataTargets)
+	}
+	return nil
+}
+
+// validateBulkMetadataFields checks the patch and that each target identifies a single version, once
+func validateBulkMetadataFields(body model.BulkMetadata) []model.FieldError {
+	v := &metadataValidation{errors: validateMetadataPatch("/patch", body.Patch)}
+
+	seen := map[model.BulkMetadataTarget]int{}
+	for i, target := range body.Targets {
+		pointer := fmt.Sprintf("/targets/%d", i)
+		v.required(pointer+"/dataset_id", target.DatasetID)
+		v.required(pointer+"/edition", target.Edition)
+		v.required(pointer+"/version", target.Version)
+
+		if first, ok := seen[target]; ok {
+			v.add(pointer, fmt.Sprintf("duplicates /targets/%d", first))
+			continue
+		}
+		seen[target] = i
+	}
+	return v.errors
+}
+
+// applyBulkMetadata patches each target in the collection using at most maxWorkers concurrent updates, returning the
+// results in the same order as the targets. Targets not started before the request is cancelled are reported as failed
+func applyBulkMetadata(ctx context.Context, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID string, c zebedeeclient.Collection, body model.BulkMetadata, maxWorkers int) []model.BulkMetadataResult {
+	results := make([]model.BulkMetadataResult, len(body.Targets))
+	started := forEachBounded(ctx, len(body.Targets), maxWorkers, func(i int) {
+		target := body.Targets[i]
+		results[i] = applyBulkMetadataTarget(ctx, req, dc, zc, userAccessToken, serviceAuthToken, collectionID, datasetStateInCollection(c, target.DatasetID), target, body.Patch)
+	})
+
+	for i := started; i < len(body.Targets); i++ {
+		errResponse := newErrorResponse(req, errCodeInternalError, "request cancelled before the version was updated")
+		results[i] = model.BulkMetadataResult{
+			BulkMetadataTarget: body.Targets[i],
+			Status:             http.StatusInternalServerError,
+			Changes:            []model.FieldChange{},
+			Error:              &errResponse,
+		}
+	}
+	return results
+}
+
+// applyBulkMetadataTarget patches the current editable metadata of a version and writes it through the same path as
+// putEditableMetadata, keeping the given state of the dataset in the collection. Versions the patch does not change are
+// left untouched
+func applyBulkMetadataTarget(ctx context.Context, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID, collectionState string, target model.BulkMetadataTarget, patch map[string]json.RawMessage) model.BulkMetadataResult {
+	result := model.BulkMetadataResult{
+		BulkMetadataTarget: target,
+		Changes:            []model.FieldChange{},
+	}
+	logInfo := log.Data{"datasetID": target.DatasetID, "edition": target.Edition, "version": target.Version}
+
+	fail := func(service, message string, err error) model.BulkMetadataResult {
+		log.Error(ctx, "bulk metadata update failed for version: "+message, err, logInfo)
+		errResponse, status := newUpstreamErrorResponse(req, service, message, err)
+		result.Status = status
+		result.Error = &errResponse
+		return result
+	}
+
+	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, target.DatasetID, target.Edition, target.Version)
+	if err != nil {
+		return fail(datasetAPIService, "failed to get version details", err)
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, target.DatasetID)
+	if err != nil {
+		return fail(datasetAPIService, "failed to get dataset details", err)
+	}
+
+	currentDataset := datasetclient.DatasetDetails{}
+	if d.Next != nil {
+		currentDataset = *d.Next
+	}
+	current := mapper.PutMetadata(model.EditMetadata{Dataset: currentDataset, Version: v})
+
+	patched, err := mapper.PatchEditableMetadata(current, patch)
+	if err != nil {
+		errResponse := newErrorResponse(req, errCodeInternalError, "failed to apply patch")
+		result.Status = http.StatusInternalServerError
+		result.Error = &errResponse
+		return result
+	}
+
+	result.Changes = mapper.EditableMetadataChanges(current, patched)
+	if len(result.Changes) == 0 {
+		result.Status = http.StatusOK
+		return result
+	}
+
+	_, err = writeEditableMetadata(ctx, dc, zc, userAccessToken, serviceAuthToken, collectionID, target.DatasetID, target.Edition, target.Version, patched, headers.ETag, collectionState)
+	var writeErr metadataWriteError
+	if errors.As(err, &writeErr) {
+		return fail(writeErr.service, writeErr.message, writeErr.err)
+	}
+
+	result.Status = http.StatusOK
+	return result
+}
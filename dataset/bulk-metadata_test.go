@@ -0,0 +1,222 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPostBulkMetadata(t *testing.T) {
+	t.Parallel()
+
+	const maxWorkers = 2
+	const target = "/collections/{collectionID}/bulk-metadata"
+
+	bulkBody := model.BulkMetadata{
+		Patch: map[string]json.RawMessage{"release_frequency": json.RawMessage(`"quarterly"`)},
+		Targets: []model.BulkMetadataTarget{
+			{DatasetID: "cpih01", Edition: "time-series", Version: "1"},
+			{DatasetID: "TS009", Edition: "2021", Version: "2"},
+			{DatasetID: "TS010", Edition: "2021", Version: "1"},
+		},
+	}
+
+	Convey("test postBulkMetadata", t, func() {
+
+		var mu sync.Mutex
+		datasets := map[string]datasetclient.DatasetDetails{
+			"cpih01": {ID: "cpih01", Title: "CPIH", ReleaseFrequency: "monthly"},
+			"TS009":  {ID: "TS009", Title: "Sex", ReleaseFrequency: "monthly"},
+			"TS010":  {ID: "TS010", Title: "Age", ReleaseFrequency: "quarterly"},
+		}
+
+		mockDatasetClient := &DatasetClientMock{
+			GetVersionWithHeadersFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return datasetclient.Version{ID: datasetID + "-version", ReleaseDate: "2021-01-01T00:00:00.000Z"}, datasetclient.ResponseHeaders{ETag: datasetID + "-etag"}, nil
+			},
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				d := datasets[datasetID]
+				return datasetclient.Dataset{ID: datasetID, Next: &d}, nil
+			},
+			PutMetadataFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "", nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID, Datasets: []zebedeeclient.CollectionItem{{ID: "cpih01", State: "Complete"}}}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+
+		handler := PostBulkMetadata(mockDatasetClient, mockZebedeeClient, "", maxWorkers)
+
+		// the collection is taken from the path rather than the Collection-Id header
+		doRequest := func(body interface{}, userAuthToken string) *httptest.ResponseRecorder {
+			return doTestRequest(target, newTestRequest("POST", "/collections/testcollection/bulk-metadata", body, "", userAuthToken), handler, nil)
+		}
+
+		decodeReport := func(rec *httptest.ResponseRecorder) model.BulkMetadataReport {
+			var report model.BulkMetadataReport
+			So(json.Unmarshal(rec.Body.Bytes(), &report), ShouldBeNil)
+			return report
+		}
+
+		Convey("on success", func() {
+			rec := doRequest(bulkBody, testUserAuthToken)
+
+			Convey("returns 200 with a result for each target in order", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				report := decodeReport(rec)
+				So(report.Succeeded, ShouldEqual, 3)
+				So(report.Failed, ShouldEqual, 0)
+				So(report.Results, ShouldHaveLength, 3)
+				So(report.Results[0].BulkMetadataTarget, ShouldResemble, bulkBody.Targets[0])
+				So(report.Results[0].Status, ShouldEqual, http.StatusOK)
+				So(report.Results[0].Changes, ShouldResemble, []model.FieldChange{
+					{Field: "release_frequency", Current: json.RawMessage(`"monthly"`), Planned: json.RawMessage(`"quarterly"`)},
+				})
+				So(report.Results[2].Changes, ShouldBeEmpty)
+			})
+
+			Convey("writes the patched metadata with each version's etag and uses the path collection", func() {
+				So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 2)
+				for _, call := range mockDatasetClient.PutMetadataCalls() {
+					So(call.CollectionID, ShouldEqual, "testcollection")
+					So(call.VersionEtag, ShouldEqual, call.DatasetID+"-etag")
+					So(call.Metadata.ReleaseFrequency, ShouldEqual, "quarterly")
+					So(call.Metadata.Title, ShouldEqual, datasets[call.DatasetID].Title)
+				}
+				So(len(mockZebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 2)
+			})
+
+			Convey("gets the collection once and keeps the state of each dataset in it", func() {
+				So(len(mockZebedeeClient.GetCollectionCalls()), ShouldEqual, 1)
+				So(mockZebedeeClient.GetCollectionCalls()[0].CollectionID, ShouldEqual, "testcollection")
+
+				states := map[string]string{}
+				for _, call := range mockZebedeeClient.PutDatasetInCollectionCalls() {
+					states[call.DatasetID] = call.State
+				}
+				So(states, ShouldResemble, map[string]string{"cpih01": "Complete", "TS009": "InProgress"})
+			})
+		})
+
+		Convey("returns the upstream status without updating anything if the collection cannot be got", func() {
+			mockZebedeeClient.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{}, &testCliError{}
+			}
+			rec := doRequest(bulkBody, testUserAuthToken)
+
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+			So(decodeErrorResponse(rec.Body.String()).Message, ShouldEqual, "failed to get collection details")
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+
+		Convey("reports the failure of one target without stopping the others", func() {
+			mockDatasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				if datasetID == "TS009" {
+					return "", testStatusError{http.StatusConflict}
+				}
+				return "", nil
+			}
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				if datasetID == "TS010" {
+					return datasetclient.Version{}, datasetclient.ResponseHeaders{}, errors.New("test dataset API error")
+				}
+				return datasetclient.Version{ID: datasetID + "-version"}, datasetclient.ResponseHeaders{ETag: datasetID + "-etag"}, nil
+			}
+			rec := doRequest(bulkBody, testUserAuthToken)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			report := decodeReport(rec)
+			So(report.Succeeded, ShouldEqual, 1)
+			So(report.Failed, ShouldEqual, 2)
+			So(report.Results[0].Error, ShouldBeNil)
+			So(report.Results[1].Status, ShouldEqual, http.StatusConflict)
+			So(report.Results[1].Error.Message, ShouldEqual, "error updating metadata")
+			So(report.Results[1].Error.UpstreamService, ShouldEqual, "dataset-api")
+			So(report.Results[2].Status, ShouldEqual, http.StatusInternalServerError)
+			So(report.Results[2].Error.Message, ShouldEqual, "failed to get version details")
+		})
+
+		Convey("errors if no token is passed", func() {
+			rec := doRequest(bulkBody, "")
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if there is no patch", func() {
+			rec := doRequest(model.BulkMetadata{Targets: bulkBody.Targets}, testUserAuthToken)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "patch is required"})
+		})
+
+		Convey("errors if there are too many targets", func() {
+			body := model.BulkMetadata{Patch: bulkBody.Patch, Targets: make([]model.BulkMetadataTarget, maxBulkMetadataTargets+1)}
+			rec := doRequest(body, testUserAuthToken)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()).Message, ShouldEqual, "too many targets: the maximum is 100")
+		})
+
+		Convey("returns 422 for an invalid patch or targets before calling the dataset API", func() {
+			body := model.BulkMetadata{
+				Patch: map[string]json.RawMessage{"dimensions": json.RawMessage(`[]`), "contacts": json.RawMessage(`[{"email":"not an email"}]`)},
+				Targets: []model.BulkMetadataTarget{
+					{DatasetID: "cpih01", Edition: "time-series", Version: "1"},
+					{DatasetID: "cpih01", Edition: "time-series"},
+					{DatasetID: "cpih01", Edition: "time-series", Version: "1"},
+				},
+			}
+			rec := doRequest(body, testUserAuthToken)
+
+			So(rec.Code, ShouldEqual, http.StatusUnprocessableEntity)
+			var response model.ValidationError
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(response.Errors, ShouldResemble, []model.FieldError{
+				{Pointer: "/patch/dimensions", Message: "is not an editable metadata field"},
+				{Pointer: "/targets/1/version", Message: "must not be blank"},
+				{Pointer: "/targets/2", Message: "duplicates /targets/0"},
+			})
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+
+		Convey("reports targets not started before the request is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				cancel()
+				return datasetclient.Version{}, datasetclient.ResponseHeaders{}, ctx.Err()
+			}
+			req := newTestRequest("POST", "/collections/testcollection/bulk-metadata", nil, "", testUserAuthToken).WithContext(ctx)
+
+			results := applyBulkMetadata(ctx, req, mockDatasetClient, mockZebedeeClient, testUserAuthToken, "", testCollectionID, zebedeeclient.Collection{}, bulkBody, 1)
+
+			So(results, ShouldHaveLength, 3)
+			So(results[0].Error.Message, ShouldEqual, "failed to get version details")
+			So(results[2].Error.Message, ShouldEqual, "request cancelled before the version was updated")
+			So(results[2].BulkMetadataTarget, ShouldResemble, bulkBody.Targets[2])
+		})
+	})
+}
@@ -21,9 +21,11 @@ type DatasetClient interface {
 	GetVersionWithHeaders(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error)
 	GetInstance(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID, ifMatch string) (i datasetclient.Instance, eTag string, err error)
 	PutDataset(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error
-	PutVersion(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version) error
+	PutVersion(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (eTag string, err error)
 	PutInstance(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (eTag string, err error)
-	PutMetadata(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, metadata datasetclient.EditableMetadata, versionEtag string) error
+	PutMetadata(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, metadata datasetclient.EditableMetadata, versionEtag string) (eTag string, err error)
+	CreateDataset(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) (m datasetclient.Dataset, err error)
+	DeleteDataset(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) error
 }
 
 type ZebedeeClient interface {
@@ -33,5 +35,5 @@ type ZebedeeClient interface {
 }
 
 type BabbageClient interface {
-	GetTopics(ctx context.Context, userAccessToken string) (result babbageclient.TopicsResult, err error)
+	GetTopicTree(ctx context.Context, userAccessToken, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error)
 }
@@ -0,0 +1,39 @@
+package dataset
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// telephoneRegexp matches telephone numbers made up of digits, spaces, hyphens and brackets, with an optional leading +
+var telephoneRegexp = regexp.MustCompile(`^\+?[0-9 ()-]*[0-9][0-9 ()-]*$`)
+
+// validateContacts checks that each contact has a name or an email and that any email or telephone number is well
+// formed, returning an error for each invalid field identified by a JSON pointer below the given pointer to the list
+func validateContacts(pointer string, contacts []datasetclient.Contact) []model.FieldError {
+	fieldErrors := []model.FieldError{}
+	for i, contact := range contacts {
+		contactPointer := fmt.Sprintf("%s/%d", pointer, i)
+		if strings.TrimSpace(contact.Name) == "" && strings.TrimSpace(contact.Email) == "" {
+			fieldErrors = append(fieldErrors, model.FieldError{Pointer: contactPointer, Message: "must have a name or an email"})
+		}
+		if contact.Email != "" && !isValidEmail(contact.Email) {
+			fieldErrors = append(fieldErrors, model.FieldError{Pointer: contactPointer + "/email", Message: "must be a valid email address"})
+		}
+		if contact.Telephone != "" && !telephoneRegexp.MatchString(contact.Telephone) {
+			fieldErrors = append(fieldErrors, model.FieldError{Pointer: contactPointer + "/telephone", Message: "must be a valid telephone number"})
+		}
+	}
+	return fieldErrors
+}
+
+// isValidEmail reports whether the value is a bare email address, without a display name
+func isValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
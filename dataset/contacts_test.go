@@ -0,0 +1,51 @@
+package dataset
+
+import (
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitValidateContacts(t *testing.T) {
+	t.Parallel()
+
+	Convey("test validateContacts", t, func() {
+		Convey("accepts well formed contacts", func() {
+			contacts := []datasetclient.Contact{
+				{Name: "contact", Email: "contact@ons.gov.uk", Telephone: "+44 (0)1633 456789"},
+				{Name: "name only"},
+				{Email: "email.only@ons.gov.uk", Telephone: "029-2034"},
+			}
+			So(validateContacts("/contacts", contacts), ShouldBeEmpty)
+		})
+
+		Convey("rejects a contact with neither name nor email", func() {
+			fieldErrors := validateContacts("/contacts", []datasetclient.Contact{{Name: "contact"}, {Telephone: "01633 456789"}})
+			So(fieldErrors, ShouldResemble, []model.FieldError{{Pointer: "/contacts/1", Message: "must have a name or an email"}})
+		})
+
+		Convey("rejects invalid emails", func() {
+			for _, email := range []string{"not-an-email", "contact@", "Contact <contact@ons.gov.uk>"} {
+				fieldErrors := validateContacts("/contacts", []datasetclient.Contact{{Name: "contact", Email: email}})
+				So(fieldErrors, ShouldResemble, []model.FieldError{{Pointer: "/contacts/0/email", Message: "must be a valid email address"}})
+			}
+		})
+
+		Convey("returns an error for every invalid field", func() {
+			fieldErrors := validateContacts("/contacts", []datasetclient.Contact{{Email: "not-an-email", Telephone: "phone"}})
+			So(fieldErrors, ShouldHaveLength, 2)
+			So(fieldErrors[0].Pointer, ShouldEqual, "/contacts/0/email")
+			So(fieldErrors[1].Pointer, ShouldEqual, "/contacts/0/telephone")
+		})
+
+		Convey("rejects invalid telephone numbers", func() {
+			for _, telephone := range []string{"phone", "01633 ext 456", "+", "44+1633"} {
+				fieldErrors := validateContacts("/contacts", []datasetclient.Contact{{Name: "contact", Telephone: telephone}})
+				So(fieldErrors, ShouldResemble, []model.FieldError{{Pointer: "/contacts/0/telephone", Message: "must be a valid telephone number"}})
+			}
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	dprequest "github.com/ONSdigital/dp-net/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Upstream services reported in error responses
+const (
+	datasetAPIService = "dataset-api"
+	zebedeeService    = "zebedee"
+	babbageService    = "babbage"
+)
+
+// Error codes returned in error responses. These are part of the API contract with Florence and must not change
+const (
+	errCodeMissingUserAccessToken = "missing_user_access_token"
+	errCodeMissingCollectionID    = "missing_collection_id"
+	errCodeInvalidQueryParameter  = "invalid_query_parameter"
+	errCodeInvalidRequestBody     = "invalid_request_body"
+	errCodeValidationFailed       = "validation_failed"
+	errCodeUnsupportedMediaType   = "unsupported_media_type"
+	errCodeBadRequest             = "bad_request"
+	errCodeUnauthorized           = "unauthorized"
+	errCodeForbidden              = "forbidden"
+	errCodeNotFound               = "not_found"
+	errCodeConflict               = "conflict"
+	errCodePreconditionFailed     = "precondition_failed"
+	errCodePreconditionRequired   = "precondition_required"
+	errCodeUpstreamError          = "upstream_error"
+	errCodeNotInCollection        = "not_in_collection"
+	errCodeInternalError          = "internal_error"
+)
+
+var (
+	errNoUserAccessToken = errors.New("no user access token header set")
+	errNoCollectionID    = errors.New("no collection ID header set")
+)
+
+// passedThroughStatusCodes maps the upstream status codes that are returned to the caller to their error code.
+// Any other upstream status results in a 500
+var passedThroughStatusCodes = map[int]string{
+	http.StatusBadRequest:         errCodeBadRequest,
+	http.StatusUnauthorized:       errCodeUnauthorized,
+	http.StatusForbidden:          errCodeForbidden,
+	http.StatusNotFound:           errCodeNotFound,
+	http.StatusConflict:           errCodeConflict,
+	http.StatusPreconditionFailed: errCodePreconditionFailed,
+}
+
+// newErrorResponse creates an error response for the request, including its request ID
+func newErrorResponse(req *http.Request, code, message string) model.ErrorResponse {
+	requestID := dprequest.GetRequestId(req.Context())
+	if requestID == "" {
+		requestID = req.Header.Get(dprequest.RequestHeaderKey)
+	}
+
+	return model.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+	}
+}
+
+// newUpstreamErrorResponse creates an error response for an error returned by an upstream service,
+// returning it along with the status code to respond with
+func newUpstreamErrorResponse(req *http.Request, service, message string, err error) (model.ErrorResponse, int) {
+	upstreamStatus := upstreamStatusCode(err)
+	status := errorStatusCode(err)
+
+	code := errCodeUpstreamError
+	if c, ok := passedThroughStatusCodes[status]; ok {
+		code = c
+	}
+
+	errResponse := newErrorResponse(req, code, message)
+	errResponse.UpstreamService = service
+	errResponse.UpstreamStatus = upstreamStatus
+	return errResponse, status
+}
+
+// writeErrorResponse writes a JSON error response with the given status and error code
+func writeErrorResponse(w http.ResponseWriter, req *http.Request, status int, code, message string) {
+	writeJSONError(w, req, status, newErrorResponse(req, code, message))
+}
+
+// writeUpstreamErrorResponse writes a JSON error response for an error returned by an upstream service,
+// passing through the upstream status if it is one the caller can act on
+func writeUpstreamErrorResponse(w http.ResponseWriter, req *http.Request, service, message string, err error) {
+	errResponse, status := newUpstreamErrorResponse(req, service, message, err)
+	log.Error(req.Context(), "upstream error", err, log.Data{"setting-response-status": status, "upstream-service": service})
+	writeJSONError(w, req, status, errResponse)
+}
+
+// writeHeadersErrorResponse writes the error response for a failed access token and collection header check
+func writeHeadersErrorResponse(w http.ResponseWriter, req *http.Request, err error) {
+	code := errCodeMissingCollectionID
+	if err == errNoUserAccessToken {
+		code = errCodeMissingUserAccessToken
+	}
+	writeErrorResponse(w, req, http.StatusBadRequest, code, err.Error())
+}
+
+func writeJSONError(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		log.Error(req.Context(), "error marshalling error response to json", err)
+		http.Error(w, "error marshalling error response to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(b); err != nil {
+		log.Error(req.Context(), "failed to write error response body", err)
+	}
+}
+
+// upstreamStatusCode returns the status code returned by the upstream service, or 0 if the error did not come from a response
+func upstreamStatusCode(err error) int {
+	var cliErr ClientError
+	if errors.As(err, &cliErr) {
+		return cliErr.Code()
+	}
+
+	var zebedeeErr zebedeeclient.ErrInvalidZebedeeResponse
+	if errors.As(err, &zebedeeErr) {
+		return zebedeeErr.ActualCode
+	}
+	return 0
+}
+
+// errorStatusCode returns the status code to respond with for an error returned by an upstream client
+func errorStatusCode(err error) int {
+	status := upstreamStatusCode(err)
+	if _, ok := passedThroughStatusCodes[status]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,81 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testStatusError struct {
+	code int
+}
+
+func (e testStatusError) Error() string { return fmt.Sprintf("upstream responded with %d", e.code) }
+func (e testStatusError) Code() int     { return e.code }
+
+func TestUnitErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	Convey("test writeUpstreamErrorResponse", t, func() {
+		req := httptest.NewRequest("GET", "/datasets", nil)
+		req.Header.Set("X-Request-Id", "test-request-id")
+
+		Convey("passes through the upstream status codes the caller can act on", func() {
+			expectedCodes := map[int]string{
+				http.StatusBadRequest:         "bad_request",
+				http.StatusUnauthorized:       "unauthorized",
+				http.StatusForbidden:          "forbidden",
+				http.StatusNotFound:           "not_found",
+				http.StatusConflict:           "conflict",
+				http.StatusPreconditionFailed: "precondition_failed",
+			}
+			for status, code := range expectedCodes {
+				w := httptest.NewRecorder()
+				writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating dataset", testStatusError{status})
+
+				So(w.Code, ShouldEqual, status)
+				So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+				So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{
+					Code:            code,
+					Message:         "error updating dataset",
+					UpstreamService: "dataset-api",
+					UpstreamStatus:  status,
+					RequestID:       "test-request-id",
+				})
+			}
+		})
+
+		Convey("returns 500 for any other upstream status", func() {
+			w := httptest.NewRecorder()
+			writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating dataset", testStatusError{http.StatusBadGateway})
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(w.Body.String()).Code, ShouldEqual, "upstream_error")
+			So(decodeErrorResponse(w.Body.String()).UpstreamStatus, ShouldEqual, http.StatusBadGateway)
+		})
+
+		Convey("reads the status code from wrapped and zebedee errors", func() {
+			w := httptest.NewRecorder()
+			err := fmt.Errorf("wrapped: %w", zebedeeclient.ErrInvalidZebedeeResponse{ActualCode: http.StatusForbidden})
+			writeUpstreamErrorResponse(w, req, zebedeeService, "error adding dataset to collection", err)
+
+			So(w.Code, ShouldEqual, http.StatusForbidden)
+			So(decodeErrorResponse(w.Body.String()).UpstreamService, ShouldEqual, "zebedee")
+		})
+
+		Convey("returns 500 for errors without a status code", func() {
+			w := httptest.NewRecorder()
+			writeUpstreamErrorResponse(w, req, babbageService, "error getting topics", errors.New("connection refused"))
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(w.Body.String()).UpstreamStatus, ShouldEqual, 0)
+		})
+	})
+}
@@ -9,42 +9,68 @@ import (
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
-// GetAll returns a mapped list of all datasets
-func GetAll(dc DatasetClient, batchSize, maxWorkers int) http.HandlerFunc {
+// GetAll returns a mapped, filtered and paginated list of datasets
+func GetAll(dc DatasetClient, serviceAuthToken string, batchSize, maxWorkers int) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getAll(w, r, dc, accessToken, collectionID, lang, batchSize, maxWorkers)
+		getAll(w, r, dc, accessToken, serviceAuthToken, collectionID, lang, batchSize, maxWorkers)
 	})
 }
 
-func getAll(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, collectionID, lang string, batchSize, maxWorkers int) {
+func getAll(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, lang string, batchSize, maxWorkers int) {
 	ctx := req.Context()
 
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
-	log.Info(ctx, "calling get datasets")
+	offset, err := getIntQueryParam(req, "offset", 0)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
+	limit, err := getIntQueryParam(req, "limit", 0)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
+	query := req.URL.Query().Get("q")
+	datasetType := req.URL.Query().Get("type")
+
+	logInfo := map[string]interface{}{
+		"offset": offset,
+		"limit":  limit,
+		"q":      query,
+		"type":   datasetType,
+	}
+
+	log.Info(ctx, "calling get datasets", log.Data(logInfo))
 
-	datasets, err := dc.GetDatasetsInBatches(ctx, userAccessToken, "", collectionID, batchSize, maxWorkers)
+	datasets, err := dc.GetDatasetsInBatches(ctx, userAccessToken, serviceAuthToken, collectionID, batchSize, maxWorkers)
 	if err != nil {
 		log.Error(ctx, "error getting all datasets from dataset API", err)
-		http.Error(w, "error getting all datasets from dataset API", http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error getting all datasets from dataset API", err)
 		return
 	}
 
 	mapped := mapper.AllDatasets(datasets)
+	filtered := mapper.FilterDatasets(mapped, query, datasetType)
+	page := mapper.DatasetsPage(filtered, offset, limit)
 
-	b, err := json.Marshal(mapped)
+	b, err := json.Marshal(page)
 	if err != nil {
 		log.Error(ctx, "error marshalling response to json", err)
-		http.Error(w, "error marshalling response to json", http.StatusInternalServerError)
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(b)
 
-	log.Info(ctx, "get all: request successful")
+	log.Info(ctx, "get all: request successful", log.Data(logInfo))
 }
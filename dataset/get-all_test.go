@@ -2,6 +2,7 @@ package dataset
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -30,11 +32,18 @@ func TestUnitGetAllDatasets(t *testing.T) {
 			ID: "id-2",
 			Next: &datasetclient.DatasetDetails{
 				Title: "Test title 2",
+				Type:  "cantabular_flexible_table",
+			},
+		},
+		{
+			ID: "id-3",
+			Next: &datasetclient.DatasetDetails{
+				Title: "Another title",
 			},
 		},
 	}
 
-	expectedSuccessResponse := "[{\"id\":\"id-1\",\"title\":\"Test title 1\"},{\"id\":\"id-2\",\"title\":\"Test title 2\"}]"
+	expectedSuccessResponse := "{\"items\":[{\"id\":\"id-3\",\"title\":\"Another title\"},{\"id\":\"id-1\",\"title\":\"Test title 1\"},{\"id\":\"id-2\",\"title\":\"Test title 2\",\"type\":\"cantabular_flexible_table\"}],\"count\":3,\"offset\":0,\"limit\":3,\"total_count\":3}"
 
 	Convey("test getAllDatasets", t, func() {
 		Convey("on success", func() {
@@ -50,7 +59,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, datasetsBatchSize, datasetsMaxWorkers))
+			router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "", datasetsBatchSize, datasetsMaxWorkers))
 
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
@@ -62,6 +71,96 @@ func TestUnitGetAllDatasets(t *testing.T) {
 				response := rec.Body.String()
 				So(response, ShouldEqual, expectedSuccessResponse)
 			})
+
+			Convey("sends the service auth token with the user's token", func() {
+				router := mux.NewRouter()
+				router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "service-token", datasetsBatchSize, datasetsMaxWorkers))
+				router.ServeHTTP(rec, req)
+
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				call := mockDatasetClient.GetDatasetsInBatchesCalls()[0]
+				So(call.UserAuthToken, ShouldEqual, "testuser")
+				So(call.ServiceAuthToken, ShouldEqual, "service-token")
+			})
+		})
+
+		Convey("applies query parameters", func() {
+
+			mockDatasetClient := &DatasetClientMock{
+				GetDatasetsInBatchesFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, batchSize int, maxWorkers int) (datasetclient.List, error) {
+					return datasetclient.List{Items: mockedDatasetResponse}, nil
+				},
+			}
+
+			router := mux.NewRouter()
+			router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "", datasetsBatchSize, datasetsMaxWorkers))
+
+			doRequest := func(target string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest("GET", target, nil)
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec
+			}
+
+			Convey("limit and offset return the requested page", func() {
+				rec := doRequest("/datasets?limit=1&offset=1")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var page model.DatasetsPage
+				So(json.Unmarshal(rec.Body.Bytes(), &page), ShouldBeNil)
+				So(page.Items, ShouldResemble, []model.Dataset{{ID: "id-1", Title: "Test title 1"}})
+				So(page.Count, ShouldEqual, 1)
+				So(page.Offset, ShouldEqual, 1)
+				So(page.Limit, ShouldEqual, 1)
+				So(page.TotalCount, ShouldEqual, 3)
+			})
+
+			Convey("q filters by title or ID", func() {
+				rec := doRequest("/datasets?q=TEST")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var page model.DatasetsPage
+				So(json.Unmarshal(rec.Body.Bytes(), &page), ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 2)
+				So(page.Items[0].ID, ShouldEqual, "id-1")
+				So(page.Items[1].ID, ShouldEqual, "id-2")
+
+				rec = doRequest("/datasets?q=id-3")
+				So(json.Unmarshal(rec.Body.Bytes(), &page), ShouldBeNil)
+				So(page.Items, ShouldResemble, []model.Dataset{{ID: "id-3", Title: "Another title"}})
+			})
+
+			Convey("type filters by dataset type", func() {
+				rec := doRequest("/datasets?type=cantabular_flexible_table")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var page model.DatasetsPage
+				So(json.Unmarshal(rec.Body.Bytes(), &page), ShouldBeNil)
+				So(page.TotalCount, ShouldEqual, 1)
+				So(page.Items[0].ID, ShouldEqual, "id-2")
+			})
+
+			Convey("an offset beyond the results returns an empty page", func() {
+				rec := doRequest("/datasets?offset=10")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(rec.Body.String(), ShouldEqual, "{\"items\":[],\"count\":0,\"offset\":3,\"limit\":3,\"total_count\":3}")
+			})
+
+			Convey("an invalid limit returns 400", func() {
+				rec := doRequest("/datasets?limit=-1")
+				So(rec.Code, ShouldEqual, http.StatusBadRequest)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_query_parameter", Message: "invalid limit query parameter: -1"})
+				So(len(mockDatasetClient.GetDatasetsInBatchesCalls()), ShouldEqual, 0)
+			})
+
+			Convey("an invalid offset returns 400", func() {
+				rec := doRequest("/datasets?offset=abc")
+				So(rec.Code, ShouldEqual, http.StatusBadRequest)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_query_parameter", Message: "invalid offset query parameter: abc"})
+				So(len(mockDatasetClient.GetDatasetsInBatchesCalls()), ShouldEqual, 0)
+			})
 		})
 
 		Convey("errors if no headers are passed", func() {
@@ -77,7 +176,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, datasetsBatchSize, datasetsMaxWorkers))
+				router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "", datasetsBatchSize, datasetsMaxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -87,7 +186,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no collection ID header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 				})
 			})
 
@@ -96,7 +195,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, datasetsBatchSize, datasetsMaxWorkers))
+				router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "", datasetsBatchSize, datasetsMaxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -106,7 +205,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no user access token header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 				})
 			})
 		})
@@ -124,7 +223,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, datasetsBatchSize, datasetsMaxWorkers))
+			router.Path("/datasets").HandlerFunc(GetAll(mockDatasetClient, "", datasetsBatchSize, datasetsMaxWorkers))
 
 			Convey("returns 500 response", func() {
 				router.ServeHTTP(rec, req)
@@ -134,7 +233,7 @@ func TestUnitGetAllDatasets(t *testing.T) {
 			Convey("returns error body", func() {
 				router.ServeHTTP(rec, req)
 				response := rec.Body.String()
-				So(response, ShouldResemble, "error getting all datasets from dataset API\n")
+				So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error getting all datasets from dataset API", UpstreamService: "dataset-api"})
 			})
 
 		})
@@ -0,0 +1,142 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetCollectionDatasets returns every dataset and dataset version in a collection with their collection state
+func GetCollectionDatasets(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string, maxWorkers int) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getCollectionDatasets(w, r, dc, zc, accessToken, serviceAuthToken, maxWorkers)
+	})
+}
+
+func getCollectionDatasets(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken string, maxWorkers int) {
+	ctx := req.Context()
+
+	// the collection being listed is taken from the path rather than the Collection-Id header
+	collectionID := mux.Vars(req)["collectionID"]
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	logInfo := map[string]interface{}{
+		"collectionID": collectionID,
+	}
+
+	log.Info(ctx, "calling get collection datasets", log.Data(logInfo))
+
+	c, err := zc.GetCollection(ctx, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, "error getting collection from zebedee", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, zebedeeService, "error getting collection from zebedee", err)
+		return
+	}
+
+	lookups, err := getCollectionDatasetTitles(ctx, dc, userAccessToken, serviceAuthToken, collectionID, collectionDatasetIDs(c), maxWorkers)
+	if err != nil {
+		log.Error(ctx, "request cancelled whilst getting dataset titles", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "request cancelled whilst getting dataset titles")
+		return
+	}
+
+	titles := make(map[string]string)
+	lookupErrors := make(map[string]string)
+	for datasetID, lookup := range lookups {
+		if lookup.err != nil {
+			log.Warn(ctx, "failed to get dataset title", log.FormatErrors([]error{lookup.err}), log.Data{"collectionID": collectionID, "datasetID": datasetID})
+			lookupErrors[datasetID] = lookup.err.Error()
+			continue
+		}
+		titles[datasetID] = lookup.title
+	}
+
+	overview := mapper.CollectionOverview(c, titles, lookupErrors)
+
+	b, err := json.Marshal(overview)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get collection datasets: request successful", log.Data(logInfo))
+}
+
+// collectionDatasetIDs returns the IDs of the datasets in a collection, including those that only have a version in it,
+// without duplicates
+func collectionDatasetIDs(c zebedeeclient.Collection) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, items := range [][]zebedeeclient.CollectionItem{c.Datasets, c.DatasetVersions} {
+		for _, item := range items {
+			if item.ID == "" || seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// datasetTitleLookup holds the title of a dataset, or the reason it could not be looked up
+type datasetTitleLookup struct {
+	title string
+	err   error
+}
+
+// getCollectionDatasetTitles looks up the title of each dataset using at most maxWorkers concurrent calls. An error is
+// only returned if the context is done
+func getCollectionDatasetTitles(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID string, datasetIDs []string, maxWorkers int) (map[string]datasetTitleLookup, error) {
+	lookups := make([]datasetTitleLookup, len(datasetIDs))
+	forEachBounded(ctx, len(datasetIDs), maxWorkers, func(i int) {
+		lookups[i] = getCollectionDatasetTitle(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetIDs[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]datasetTitleLookup, len(datasetIDs))
+	for i, datasetID := range datasetIDs {
+		titles[datasetID] = lookups[i]
+	}
+	return titles, nil
+}
+
+// getCollectionDatasetTitle gets the title of the next, in collection, version of a dataset, falling back to the
+// current title
+func getCollectionDatasetTitle(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID string) datasetTitleLookup {
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		return datasetTitleLookup{err: fmt.Errorf("failed to get dataset: %w", err)}
+	}
+
+	if d.Next != nil && d.Next.Title != "" {
+		return datasetTitleLookup{title: d.Next.Title}
+	}
+	if d.Current != nil {
+		return datasetTitleLookup{title: d.Current.Title}
+	}
+	return datasetTitleLookup{}
+}
@@ -0,0 +1,122 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetCollectionDatasets(t *testing.T) {
+	t.Parallel()
+
+	mockCollection := zebedeeclient.Collection{
+		ID:   "testcollection",
+		Name: "Test collection",
+		Datasets: []zebedeeclient.CollectionItem{
+			{ID: "dataset-1", State: "InProgress", LastEditedBy: "a@ons.gov.uk"},
+		},
+		DatasetVersions: []zebedeeclient.CollectionItem{
+			{ID: "dataset-1", State: "Complete", LastEditedBy: "b@ons.gov.uk", Edition: "2021", Version: "1"},
+			{ID: "dataset-2", State: "InProgress", LastEditedBy: "a@ons.gov.uk", Title: "zebedee title", Edition: "2021", Version: "3"},
+		},
+	}
+
+	Convey("test getCollectionDatasets", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				if datasetID == "dataset-1" {
+					return datasetclient.Dataset{Current: &datasetclient.DatasetDetails{Title: "Old title"}, Next: &datasetclient.DatasetDetails{Title: "Dataset one"}}, nil
+				}
+				return datasetclient.Dataset{Current: &datasetclient.DatasetDetails{Title: "Dataset two"}}, nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return mockCollection, nil
+			},
+		}
+
+		router := mux.NewRouter()
+		router.Path("/collections/{collectionID}/datasets").HandlerFunc(GetCollectionDatasets(mockDatasetClient, mockZebedeeClient, "", 2))
+
+		doRequest := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/collections/testcollection/datasets", nil)
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		Convey("returns every dataset and version in the collection with titles from the dataset API", func() {
+			rec := doRequest()
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			var overview model.CollectionOverview
+			So(json.Unmarshal(rec.Body.Bytes(), &overview), ShouldBeNil)
+			So(overview.ID, ShouldEqual, "testcollection")
+			So(overview.Datasets, ShouldResemble, []model.CollectionDataset{
+				{ID: "dataset-1", Title: "Dataset one", State: "InProgress", LastEditedBy: "a@ons.gov.uk"},
+			})
+			So(overview.DatasetVersions, ShouldResemble, []model.CollectionDatasetVersion{
+				{DatasetID: "dataset-1", DatasetTitle: "Dataset one", Edition: "2021", Version: "1", State: "Complete", LastEditedBy: "b@ons.gov.uk"},
+				{DatasetID: "dataset-2", DatasetTitle: "Dataset two", Edition: "2021", Version: "3", State: "InProgress", LastEditedBy: "a@ons.gov.uk"},
+			})
+
+			Convey("looking each dataset up once in the collection", func() {
+				So(len(mockDatasetClient.GetDatasetCurrentAndNextCalls()), ShouldEqual, 2)
+				for _, call := range mockDatasetClient.GetDatasetCurrentAndNextCalls() {
+					So(call.CollectionID, ShouldEqual, "testcollection")
+				}
+				So(mockZebedeeClient.GetCollectionCalls()[0].CollectionID, ShouldEqual, "testcollection")
+			})
+		})
+
+		Convey("keeps zebedee's title and reports the error when a dataset cannot be looked up", func() {
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{}, errors.New("dataset API unavailable")
+			}
+			rec := doRequest()
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			var overview model.CollectionOverview
+			So(json.Unmarshal(rec.Body.Bytes(), &overview), ShouldBeNil)
+			So(overview.DatasetVersions[1].DatasetTitle, ShouldEqual, "zebedee title")
+			So(overview.DatasetVersions[1].LookupError, ShouldEqual, "failed to get dataset: dataset API unavailable")
+		})
+
+		Convey("returns the upstream error if the collection cannot be found", func() {
+			mockZebedeeClient.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{}, &testCliError{}
+			}
+			rec := doRequest()
+
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+			response := decodeErrorResponse(rec.Body.String())
+			So(response.UpstreamService, ShouldEqual, "zebedee")
+			So(len(mockDatasetClient.GetDatasetCurrentAndNextCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 400 without a user access token", func() {
+			req := httptest.NewRequest("GET", "/collections/testcollection/datasets", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package dataset
+
+import (
+	"encoding/json"
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetMetadataDiff returns the fields a draft version will change on publish, compared to the published dataset and latest published version
+func GetMetadataDiff(dc DatasetClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getMetadataDiff(w, r, dc, accessToken, serviceAuthToken, collectionID, lang)
+	})
+}
+
+func getMetadataDiff(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, lang string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	log.Info(ctx, "calling get metadata diff", log.Data(logInfo))
+
+	v, err := dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
+		return
+	}
+
+	// a dataset that has never been published has no current doc, so every draft field is reported as a change
+	publishedVersion := datasetclient.Version{}
+	if d.Current != nil && d.Current.Links.LatestVersion.URL != "" {
+		publishedVersion, err = getLatestPublishedVersion(ctx, dc, userAccessToken, serviceAuthToken, collectionID, d.Current.Links.LatestVersion.URL)
+		if err != nil {
+			log.Error(ctx, "failed Get latest published version details", err, log.Data(logInfo))
+			writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get latest published version details", err)
+			return
+		}
+	}
+
+	diff := mapper.MetadataDiff(d.Current, d.Next, publishedVersion, v)
+
+	b, err := json.Marshal(diff)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	log.Info(ctx, "get metadata diff: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,112 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetMetadataDiff(t *testing.T) {
+	t.Parallel()
+
+	const reqURL = "/datasets/test-dataset/editions/test-edition/versions/2/diff"
+
+	mockedDatasetResponse := datasetclient.Dataset{
+		Current: &datasetclient.DatasetDetails{
+			ID:    "test-dataset",
+			Title: "Published title",
+			Links: datasetclient.Links{
+				LatestVersion: datasetclient.Link{URL: "http://localhost:22000/v1/datasets/test-dataset/editions/test-edition/versions/1"},
+			},
+		},
+		Next: &datasetclient.DatasetDetails{
+			ID:    "test-dataset",
+			Title: "Draft title",
+		},
+	}
+
+	mockedVersions := map[string]datasetclient.Version{
+		"1": {Edition: "test-edition", Version: 1, UsageNotes: &[]datasetclient.UsageNote{{Title: "note", Note: "text"}}},
+		"2": {Edition: "test-edition", Version: 2, UsageNotes: &[]datasetclient.UsageNote{{Title: "note", Note: "text"}}},
+	}
+
+	Convey("test getMetadataDiff", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.Dataset, error) {
+				return mockedDatasetResponse, nil
+			},
+			GetVersionFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, edition string, version string) (datasetclient.Version, error) {
+				return mockedVersions[version], nil
+			},
+		}
+
+		const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/diff"
+		handler := GetMetadataDiff(mockDatasetClient, "")
+
+		Convey("on success", func() {
+			rec := doTestRequest(target, newTestRequest("GET", reqURL, nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			Convey("returns 200 response with the changed fields", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var response model.MetadataDiff
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.DatasetID, ShouldEqual, "test-dataset")
+				So(response.Version, ShouldEqual, 2)
+				So(response.PublishedVersion, ShouldEqual, 1)
+				So(response.DatasetChanges, ShouldResemble, []model.FieldDiff{
+					{Field: "title", Published: json.RawMessage(`"Published title"`), Draft: json.RawMessage(`"Draft title"`)},
+				})
+				So(response.VersionChanges, ShouldResemble, []model.FieldDiff{})
+			})
+
+			Convey("gets the draft and latest published versions", func() {
+				So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 2)
+				So(mockDatasetClient.GetVersionCalls()[0].Version, ShouldEqual, "2")
+				So(mockDatasetClient.GetVersionCalls()[1].Version, ShouldEqual, "1")
+				So(mockDatasetClient.GetVersionCalls()[1].UserAuthToken, ShouldEqual, testUserAuthToken)
+				So(mockDatasetClient.GetVersionCalls()[1].CollectionID, ShouldEqual, testCollectionID)
+			})
+		})
+
+		Convey("compares against empty metadata when the dataset has not been published", func() {
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Next: mockedDatasetResponse.Next}, nil
+			}
+			rec := doTestRequest(target, newTestRequest("GET", reqURL, nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 1)
+
+			var response model.MetadataDiff
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(response.PublishedVersion, ShouldEqual, 0)
+			So(response.VersionChanges[0].Field, ShouldEqual, "usage_notes")
+		})
+
+		Convey("errors if no headers are passed", func() {
+			rec := doTestRequest(target, newTestRequest("GET", reqURL, nil, "", ""), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
+		})
+
+		Convey("handles error from dataset client", func() {
+			mockDatasetClient.GetVersionFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, edition string, version string) (datasetclient.Version, error) {
+				return datasetclient.Version{}, errors.New("test dataset API error")
+			}
+			rec := doTestRequest(target, newTestRequest("GET", reqURL, nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "failed to get version details", UpstreamService: "dataset-api"})
+		})
+	})
+}
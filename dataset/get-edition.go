@@ -0,0 +1,92 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetEdition returns the detail of an edition, including its latest published and unpublished versions
+func GetEdition(dc DatasetClient, serviceAuthToken string, batchSize, maxWorkers int) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getEdition(w, r, dc, accessToken, serviceAuthToken, collectionID, batchSize, maxWorkers)
+	})
+}
+
+func getEdition(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID string, batchSize, maxWorkers int) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	editionID := vars["editionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   editionID,
+	}
+
+	log.Info(ctx, "calling get edition", log.Data(logInfo))
+
+	detail, err := getEditionDetail(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, editionID, batchSize, maxWorkers)
+	if err != nil {
+		log.Error(ctx, "error getting edition detail from dataset API", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error getting edition detail from dataset API", err)
+		return
+	}
+
+	writeEditionDetail(w, req, http.StatusOK, detail, logInfo)
+
+	log.Info(ctx, "get edition: request successful", log.Data(logInfo))
+}
+
+// getEditionDetail gets the dataset, edition and all of the edition's versions and maps them to the edition detail
+func getEditionDetail(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID, editionID string, batchSize, maxWorkers int) (model.EditionDetail, error) {
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		return model.EditionDetail{}, err
+	}
+
+	edition, err := dc.GetEdition(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, editionID)
+	if err != nil {
+		return model.EditionDetail{}, err
+	}
+
+	versions, err := dc.GetVersionsInBatches(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, editionID, batchSize, maxWorkers)
+	if err != nil {
+		return model.EditionDetail{}, err
+	}
+
+	detail := mapper.EditionDetail(ctx, d, edition, versions)
+	detail.DatasetID = datasetID
+	return detail, nil
+}
+
+func writeEditionDetail(w http.ResponseWriter, req *http.Request, status int, detail model.EditionDetail, logInfo map[string]interface{}) {
+	ctx := req.Context()
+
+	b, err := json.Marshal(detail)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+	}
+}
@@ -0,0 +1,108 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetEdition(t *testing.T) {
+	t.Parallel()
+
+	const batchSize = 10
+	const maxWorkers = 3
+
+	mockedDatasetResponse := datasetclient.Dataset{
+		ID:   "test-dataset",
+		Next: &datasetclient.DatasetDetails{Title: "Test title"},
+	}
+
+	mockedEditionResponse := datasetclient.Edition{
+		Edition: "2021",
+		State:   "edition-confirmed",
+		Links:   datasetclient.Links{Dataset: datasetclient.Link{ID: "test-dataset"}},
+	}
+
+	mockedVersionsResponse := datasetclient.VersionsList{Items: []datasetclient.Version{
+		{ID: "version-1", Version: 1, State: "published", ReleaseDate: "2020-11-07T00:00:00Z"},
+		{ID: "version-2", Version: 2, State: "associated"},
+	}}
+
+	Convey("test getEdition", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.Dataset, error) {
+				return mockedDatasetResponse, nil
+			},
+			GetEditionFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string) (datasetclient.Edition, error) {
+				return mockedEditionResponse, nil
+			},
+			GetVersionsInBatchesFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, edition string, batchSize int, maxWorkers int) (datasetclient.VersionsList, error) {
+				return mockedVersionsResponse, nil
+			},
+		}
+
+		const target = "/datasets/{datasetID}/editions/{editionID}"
+		handler := GetEdition(mockDatasetClient, "", batchSize, maxWorkers)
+
+		Convey("on success", func() {
+			rec := doTestRequest(target, newTestRequest("GET", "/datasets/test-dataset/editions/2021", nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			Convey("returns 200 response with the edition detail", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+				var response model.EditionDetail
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.ID, ShouldEqual, "2021")
+				So(response.DatasetID, ShouldEqual, "test-dataset")
+				So(response.DatasetName, ShouldEqual, "Test title")
+				So(response.State, ShouldEqual, "edition-confirmed")
+				So(response.VersionCount, ShouldEqual, 2)
+				So(response.LatestPublishedVersion.ID, ShouldEqual, "version-1")
+				So(response.LatestUnpublishedVersion.ID, ShouldEqual, "version-2")
+			})
+
+			Convey("gets the versions in batches", func() {
+				So(len(mockDatasetClient.GetVersionsInBatchesCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.GetVersionsInBatchesCalls()[0].Edition, ShouldEqual, "2021")
+				So(mockDatasetClient.GetVersionsInBatchesCalls()[0].BatchSize, ShouldEqual, batchSize)
+				So(mockDatasetClient.GetVersionsInBatchesCalls()[0].MaxWorkers, ShouldEqual, maxWorkers)
+			})
+		})
+
+		Convey("errors if no headers are passed", func() {
+			rec := doTestRequest(target, newTestRequest("GET", "/datasets/test-dataset/editions/2021", nil, "", ""), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(len(mockDatasetClient.GetEditionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("passes through a not found edition", func() {
+			mockDatasetClient.GetEditionFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string) (datasetclient.Edition, error) {
+				return datasetclient.Edition{}, testStatusError{http.StatusNotFound}
+			}
+			rec := doTestRequest(target, newTestRequest("GET", "/datasets/test-dataset/editions/2021", nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+			So(decodeErrorResponse(rec.Body.String()).UpstreamService, ShouldEqual, "dataset-api")
+			So(len(mockDatasetClient.GetVersionsInBatchesCalls()), ShouldEqual, 0)
+		})
+
+		Convey("handles error getting versions", func() {
+			mockDatasetClient.GetVersionsInBatchesFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, edition string, batchSize int, maxWorkers int) (datasetclient.VersionsList, error) {
+				return datasetclient.VersionsList{}, errors.New("test dataset API error")
+			}
+			rec := doTestRequest(target, newTestRequest("GET", "/datasets/test-dataset/editions/2021", nil, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -1,10 +1,12 @@
 package dataset
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	dphandlers "github.com/ONSdigital/dp-net/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/log.go/v2/log"
@@ -12,13 +14,13 @@ import (
 )
 
 // GetEditions returns a mapped list of all editions
-func GetEditions(dc DatasetClient) http.HandlerFunc {
+func GetEditions(dc DatasetClient, serviceAuthToken string, maxWorkers int) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getEditions(w, r, dc, accessToken, collectionID, lang)
+		getEditions(w, r, dc, accessToken, serviceAuthToken, collectionID, lang, maxWorkers)
 	})
 }
 
-func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, collectionID, lang string) {
+func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, lang string, maxWorkers int) {
 	ctx := req.Context()
 
 	vars := mux.Vars(req)
@@ -27,7 +29,7 @@ func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetClient, use
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
@@ -38,43 +40,45 @@ func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetClient, use
 
 	log.Info(ctx, "calling get editions", log.Data(logInfo))
 
-	dataset, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, "", collectionID, datasetID)
+	dataset, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
 	if err != nil {
 		errMsg := fmt.Sprintf("error getting dataset from dataset API: %v", err.Error())
 		log.Error(ctx, "error getting dataset from dataset API", err, log.Data(logInfo))
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, errMsg, err)
 		return
 	}
 
-	editions, err := dc.GetEditions(ctx, userAccessToken, "", collectionID, datasetID)
+	editions, err := dc.GetEditions(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
 	if err != nil {
 		errMsg := fmt.Sprintf("error getting editions from dataset API: %v", err.Error())
 		log.Error(ctx, "error getting editions from dataset API", err, log.Data(logInfo))
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, errMsg, err)
+		return
+	}
+
+	lookups, err := getLatestVersionReleaseDates(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, editions, maxWorkers)
+	if err != nil {
+		log.Error(ctx, "request cancelled whilst getting latest versions", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "request cancelled whilst getting latest versions")
 		return
 	}
 
 	latestVersionInEdition := make(map[string]string)
-	for _, edition := range editions {
-		_, _, versionID, err := getIDsFromURL(edition.Links.LatestVersion.URL)
-		if err != nil {
-			latestVersionInEdition[edition.Edition] = ""
-			continue
-		}
-		version, err := dc.GetVersion(ctx, userAccessToken, "", "", collectionID, datasetID, edition.Edition, versionID)
-		if err != nil {
-			latestVersionInEdition[edition.Edition] = ""
-			continue
+	lookupErrors := make(map[string]string)
+	for i, edition := range editions {
+		latestVersionInEdition[edition.Edition] = lookups[i].releaseDate
+		if lookups[i].err != nil {
+			log.Warn(ctx, "failed to get latest version of edition", log.FormatErrors([]error{lookups[i].err}), log.Data{"datasetID": datasetID, "edition": edition.Edition})
+			lookupErrors[edition.Edition] = lookups[i].err.Error()
 		}
-		latestVersionInEdition[edition.Edition] = version.ReleaseDate
 	}
 
-	mapped := mapper.AllEditions(ctx, dataset, editions, latestVersionInEdition)
+	mapped := mapper.AllEditions(ctx, dataset, editions, latestVersionInEdition, lookupErrors)
 
 	b, err := json.Marshal(mapped)
 	if err != nil {
 		log.Error(ctx, "error marshalling editions response to json", err, log.Data(logInfo))
-		http.Error(w, "error marshalling editions response to json", http.StatusInternalServerError)
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling editions response to json")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -82,3 +86,36 @@ func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetClient, use
 
 	log.Info(ctx, "get editions: request successful", log.Data(logInfo))
 }
+
+// latestVersionLookup holds the release date of an edition's latest version, or the reason it could not be looked up
+type latestVersionLookup struct {
+	releaseDate string
+	err         error
+}
+
+// getLatestVersionReleaseDates looks up the latest version of each edition using at most maxWorkers concurrent calls,
+// returning the lookups in the same order as the editions. An error is only returned if the context is done
+func getLatestVersionReleaseDates(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID string, editions []datasetclient.Edition, maxWorkers int) ([]latestVersionLookup, error) {
+	lookups := make([]latestVersionLookup, len(editions))
+	forEachBounded(ctx, len(editions), maxWorkers, func(i int) {
+		lookups[i] = getLatestVersionReleaseDate(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, editions[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return lookups, nil
+}
+
+func getLatestVersionReleaseDate(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID string, edition datasetclient.Edition) latestVersionLookup {
+	_, _, versionID, err := getIDsFromURL(edition.Links.LatestVersion.URL)
+	if err != nil {
+		return latestVersionLookup{err: fmt.Errorf("invalid latest version link: %w", err)}
+	}
+
+	version, err := dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition.Edition, versionID)
+	if err != nil {
+		return latestVersionLookup{err: fmt.Errorf("failed to get latest version: %w", err)}
+	}
+	return latestVersionLookup{releaseDate: version.ReleaseDate}
+}
@@ -2,15 +2,19 @@ package dataset
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -19,6 +23,7 @@ func TestUnitGetEditions(t *testing.T) {
 	t.Parallel()
 
 	datasetID := "test-dataset"
+	maxWorkers := 3
 
 	mockedDatasetResponse := datasetclient.Dataset{
 		Next: &datasetclient.DatasetDetails{
@@ -29,9 +34,11 @@ func TestUnitGetEditions(t *testing.T) {
 	mockedEditionResponse := []datasetclient.Edition{
 		{
 			Edition: "edition-1",
+			Links:   datasetclient.Links{LatestVersion: datasetclient.Link{URL: "http://localhost:22000/v1/datasets/test-dataset/editions/edition-1/versions/1"}},
 		},
 		{
 			Edition: "edition-2",
+			Links:   datasetclient.Links{LatestVersion: datasetclient.Link{URL: "http://localhost:22000/v1/datasets/test-dataset/editions/edition-2/versions/1"}},
 		},
 	}
 
@@ -42,7 +49,7 @@ func TestUnitGetEditions(t *testing.T) {
 		ReleaseDate: "2020-11-07T00:00:00.000Z",
 	}
 
-	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"editions\":[{\"id\":\"edition-1\",\"title\":\"edition-1\",\"release_date\":\"\"},{\"id\":\"edition-2\",\"title\":\"edition-2\",\"release_date\":\"\"}]}"
+	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"editions\":[{\"id\":\"edition-1\",\"title\":\"edition-1\",\"release_date\":\"07 November 2020\"},{\"id\":\"edition-2\",\"title\":\"edition-2\",\"release_date\":\"07 November 2020\"}]}"
 
 	Convey("test getAllEditions", t, func() {
 
@@ -65,7 +72,7 @@ func TestUnitGetEditions(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
 
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
@@ -86,7 +93,7 @@ func TestUnitGetEditions(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+				router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -96,7 +103,7 @@ func TestUnitGetEditions(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no collection ID header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 				})
 			})
 
@@ -106,7 +113,7 @@ func TestUnitGetEditions(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+				router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -116,7 +123,7 @@ func TestUnitGetEditions(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no user access token header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 				})
 			})
 		})
@@ -140,7 +147,7 @@ func TestUnitGetEditions(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
 
 			Convey("returns 500 response", func() {
 				router.ServeHTTP(rec, req)
@@ -150,9 +157,107 @@ func TestUnitGetEditions(t *testing.T) {
 			Convey("returns error body", func() {
 				router.ServeHTTP(rec, req)
 				response := rec.Body.String()
-				So(response, ShouldResemble, "error getting editions from dataset API: test dataset API error\n")
+				So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error getting editions from dataset API: test dataset API error", UpstreamService: "dataset-api"})
 			})
 
 		})
+
+		Convey("reports editions whose latest version can't be looked up", func() {
+			mockDatasetClient.GetEditionsFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) ([]datasetclient.Edition, error) {
+				return append(mockedEditionResponse, datasetclient.Edition{Edition: "edition-3"}), nil
+			}
+			mockDatasetClient.GetVersionFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, editionID string, versionID string) (datasetclient.Version, error) {
+				if editionID == "edition-2" {
+					return datasetclient.Version{}, errors.New("test dataset API error")
+				}
+				return mockedVersionResponse, nil
+			}
+
+			reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
+			req := httptest.NewRequest("GET", reqURL, nil)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+
+			var response model.EditionsPage
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(response.Editions, ShouldResemble, []model.Edition{
+				{ID: "edition-1", Title: "edition-1", ReleaseDate: "07 November 2020"},
+				{ID: "edition-2", Title: "edition-2", LookupError: "failed to get latest version: test dataset API error"},
+				{ID: "edition-3", Title: "edition-3", LookupError: "invalid latest version link: not enough arguements in path"},
+			})
+		})
+
+		Convey("looks up latest versions concurrently, preserving the edition order", func() {
+			var editions []datasetclient.Edition
+			for i := 1; i <= 20; i++ {
+				edition := fmt.Sprintf("edition-%d", i)
+				editions = append(editions, datasetclient.Edition{
+					Edition: edition,
+					Links:   datasetclient.Links{LatestVersion: datasetclient.Link{URL: fmt.Sprintf("http://localhost:22000/v1/datasets/test-dataset/editions/%s/versions/%d", edition, i)}},
+				})
+			}
+
+			var running, maxRunning int32
+			mockDatasetClient.GetEditionsFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) ([]datasetclient.Edition, error) {
+				return editions, nil
+			}
+			mockDatasetClient.GetVersionFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, editionID string, versionID string) (datasetclient.Version, error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return datasetclient.Version{ReleaseDate: fmt.Sprintf("2020-11-%02sT00:00:00.000Z", versionID)}, nil
+			}
+
+			reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
+			req := httptest.NewRequest("GET", reqURL, nil)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 20)
+			So(atomic.LoadInt32(&maxRunning), ShouldBeLessThanOrEqualTo, maxWorkers)
+
+			var response model.EditionsPage
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(len(response.Editions), ShouldEqual, 20)
+			for i, edition := range response.Editions {
+				So(edition.ID, ShouldEqual, fmt.Sprintf("edition-%d", i+1))
+				So(edition.ReleaseDate, ShouldEqual, fmt.Sprintf("%02d November 2020", i+1))
+			}
+		})
+
+		Convey("stops looking up latest versions when the request is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
+			req := httptest.NewRequest("GET", reqURL, nil).WithContext(ctx)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient, "", maxWorkers))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(rec.Body.String()).Code, ShouldEqual, "internal_error")
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 0)
+		})
 	})
 }
@@ -12,6 +12,7 @@ import (
 	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	dphandlers "github.com/ONSdigital/dp-net/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
 )
@@ -25,20 +26,20 @@ type ClientError interface {
 const editionConfirmedState = "edition-confirmed"
 
 // GetEditMetadataHandler is a handler that wraps getEditMetadataHandler passing in addition arguments
-func GetMetadataHandler(dc DatasetClient, zc ZebedeeClient) http.HandlerFunc {
+func GetMetadataHandler(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getEditMetadataHandler(w, r, dc, zc, accessToken, collectionID, lang)
+		getEditMetadataHandler(w, r, dc, zc, accessToken, serviceAuthToken, collectionID, lang)
 	})
 }
 
 // getEditMetadataHandler gets the Edit Metadata page information used on the edit metadata screens
-func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, collectionID, lang string) {
+func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID, lang string) {
 	ctx := req.Context()
 
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
@@ -53,45 +54,69 @@ func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc Dataset
 		"version":   version,
 	}
 
-	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, "", "", collectionID, datasetID, edition, version)
+	carryOver, err := getListQueryParam(req, "carry_over", mapper.CarryOverFields)
+	if err != nil {
+		log.Error(ctx, "invalid query parameter", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+	logInfo["carry_over"] = carryOver
+
+	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
 	if err != nil {
 		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
-		setErrorStatusCode(req, w, err, datasetID)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
 		return
 	}
 
 	// we get the next and current doc so that we have info relating to latest published version
 	// on the current doc
-	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, "", collectionID, datasetID)
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
 	if err != nil {
 		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
-		setErrorStatusCode(req, w, err, datasetID)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
 		return
 	}
 
 	// if the version state is "edition-confirmed" it's in a pre-edited state so we get previously
 	// published version's dimensions and return those so that they are pre-populated in the browser
-	// to prevent the user having to fill these in again
+	// to prevent the user having to fill these in again. Usage notes, dimension descriptions and any
+	// selected carry over fields are also copied into the version where they haven't been filled in
 	dims := []datasetclient.VersionDimension{}
-	if v.State == editionConfirmedState && v.Version > 1 {
-		dimensions := getLatestPublishedVersionDimensions(ctx, w, req, dc, userAccessToken, collectionID, d.Current.Links.LatestVersion.URL)
-		dims = append(dims, dimensions...)
+	var inherited *model.InheritedMetadata
+	if v.State == editionConfirmedState && v.Version > 1 && d.Current != nil {
+		publishedVersion, err := getLatestPublishedVersion(ctx, dc, userAccessToken, serviceAuthToken, collectionID, d.Current.Links.LatestVersion.URL)
+		if err != nil {
+			log.Error(ctx, "failed Get latest published version details", err, log.Data(logInfo))
+			writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get latest published version details", err)
+			return
+		}
+		dims = append(dims, publishedVersion.Dimensions...)
+
+		if publishedVersion.Version > 0 {
+			var i model.InheritedMetadata
+			v, i = mapper.InheritMetadata(v, publishedVersion, carryOver)
+			inherited = &i
+		}
 	}
 
 	c, err := getCollectionDetails(ctx, zc, userAccessToken, d.Next.CollectionID)
 	if err != nil {
 		log.Error(ctx, "failed Get collection details", err, log.Data(logInfo))
-		setErrorStatusCode(req, w, err, datasetID)
+		writeUpstreamErrorResponse(w, req, zebedeeService, "failed to get collection details", err)
 		return
 	}
 
 	editMetadata := mapper.EditMetadata(d.Next, v, dims, c)
 	editMetadata.VersionEtag = headers.ETag
+	// a version shares its ETag with its instance, so the same value guards dimension changes
+	editMetadata.InstanceEtag = headers.ETag
+	editMetadata.Inherited = inherited
 
 	b, err := json.Marshal(editMetadata)
 	if err != nil {
 		log.Error(ctx, "failed marshalling page into bytes", err)
-		setErrorStatusCode(req, w, err, datasetID)
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "failed marshalling page into bytes")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -99,7 +124,6 @@ func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc Dataset
 	_, err = w.Write(b)
 	if err != nil {
 		log.Error(ctx, "failed to write bytes for http response", err, log.Data(logInfo))
-		setErrorStatusCode(req, w, err, datasetID)
 		return
 	}
 
@@ -117,21 +141,15 @@ func getCollectionDetails(ctx context.Context, zc ZebedeeClient, userAccessToken
 	}
 }
 
-func getLatestPublishedVersionDimensions(ctx context.Context, w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, collectionID, latestVersionURL string) []datasetclient.VersionDimension {
+// getLatestPublishedVersion gets the version the latest version URL points at. An empty version is returned if the URL cannot be parsed
+func getLatestPublishedVersion(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, latestVersionURL string) (datasetclient.Version, error) {
 	datasetID, editionID, versionID, err := getIDsFromURL(latestVersionURL)
 	if err != nil {
 		log.Error(ctx, "failed to parse latest version url", err)
-		return []datasetclient.VersionDimension{}
+		return datasetclient.Version{}, nil
 	}
 
-	latestPublishedVersion, err := dc.GetVersion(ctx, userAccessToken, "", "", collectionID, datasetID, editionID, versionID)
-	if err != nil {
-		log.Error(ctx, "failed Get latest published version details", err)
-		setErrorStatusCode(req, w, err, datasetID)
-		return []datasetclient.VersionDimension{}
-	}
-
-	return latestPublishedVersion.Dimensions
+	return dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, editionID, versionID)
 }
 
 func getIDsFromURL(URL string) (datasetID, editionID, versionID string, err error) {
@@ -149,14 +167,3 @@ func getIDsFromURL(URL string) (datasetID, editionID, versionID string, err erro
 	versionID = s[7]
 	return datasetID, editionID, versionID, nil
 }
-
-func setErrorStatusCode(req *http.Request, w http.ResponseWriter, err error, datasetID string) {
-	status := http.StatusInternalServerError
-	if err, ok := err.(ClientError); ok {
-		if err.Code() == http.StatusNotFound {
-			status = err.Code()
-		}
-	}
-	log.Error(req.Context(), "client error", err, log.Data{"setting-response-status": status, "datasetID": datasetID})
-	w.WriteHeader(status)
-}
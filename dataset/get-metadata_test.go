@@ -1,11 +1,14 @@
 package dataset
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
@@ -23,6 +26,42 @@ type testCliError struct{}
 func (e *testCliError) Error() string { return "client error" }
 func (e *testCliError) Code() int     { return http.StatusNotFound }
 
+// decodeErrorResponse helper function that unmarshals a JSON error response body
+func decodeErrorResponse(body string) model.ErrorResponse {
+	var errResponse model.ErrorResponse
+	json.Unmarshal([]byte(body), &errResponse)
+	return errResponse
+}
+
+// Headers set on test requests by newTestRequest
+const (
+	testCollectionID  = "testcollection"
+	testUserAuthToken = "testuser"
+)
+
+// newTestRequest creates a request with the collection ID and user access token headers set, unless they are empty.
+// A string body is sent as it is, any other body other than nil is marshalled to JSON
+func newTestRequest(method, url string, body interface{}, collectionID, userAuthToken string) *http.Request {
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = strings.NewReader(b)
+	default:
+		j, _ := json.Marshal(b)
+		r = bytes.NewReader(j)
+	}
+
+	req := httptest.NewRequest(method, url, r)
+	if collectionID != "" {
+		req.Header.Set("Collection-Id", collectionID)
+	}
+	if userAuthToken != "" {
+		req.Header.Set("X-Florence-Token", userAuthToken)
+	}
+	return req
+}
+
 // doTestRequest helper function that creates a router and mocks requests
 func doTestRequest(target string, req *http.Request, handlerFunc http.HandlerFunc, w *httptest.ResponseRecorder) *httptest.ResponseRecorder {
 	if w == nil {
@@ -44,14 +83,20 @@ func TestUnitHandlers(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	Convey("test setStatusCode", t, func() {
+	Convey("test writeUpstreamErrorResponse", t, func() {
 		Convey("test status code handles 404 response from client", func() {
 			req := httptest.NewRequest("GET", "http://localhost:24000", nil)
 			w := httptest.NewRecorder()
 			err := &testCliError{}
-			setErrorStatusCode(req, w, err, mockDatasetID)
+			writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
 
 			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{
+				Code:            "not_found",
+				Message:         "failed to get version details",
+				UpstreamService: "dataset-api",
+				UpstreamStatus:  http.StatusNotFound,
+			})
 		})
 	})
 
@@ -122,7 +167,7 @@ func TestUnitHandlers(t *testing.T) {
 			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", nil)
 			req.Header.Set("Collection-Id", mockCollectionId)
 			req.Header.Set("X-Florence-Token", mockUserAuthToken)
-			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient), nil)
+			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, ""), nil)
 
 			So(w.Code, ShouldEqual, http.StatusOK)
 			So(w.Body.String(), ShouldNotBeNil)
@@ -134,6 +179,7 @@ func TestUnitHandlers(t *testing.T) {
 			So(body.Dataset, ShouldResemble, *mockDataset.Next)
 			So(body.Dimensions, ShouldBeEmpty)
 			So(body.VersionEtag, ShouldEqual, responseHeaders.ETag)
+			So(body.InstanceEtag, ShouldEqual, responseHeaders.ETag)
 			So(body.CollectionID, ShouldEqual, mockCollectionId)
 			So(body.CollectionState, ShouldEqual, datasetCollectionItem.State)
 			So(body.CollectionLastEditedBy, ShouldEqual, datasetCollectionItem.LastEditedBy)
@@ -148,7 +194,7 @@ func TestUnitHandlers(t *testing.T) {
 			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", nil)
 			req.Header.Set("Collection-Id", mockCollectionId)
 			req.Header.Set("X-Florence-Token", mockUserAuthToken)
-			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient), nil)
+			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, ""), nil)
 
 			So(w.Code, ShouldEqual, http.StatusOK)
 			So(w.Body.String(), ShouldNotBeNil)
@@ -160,10 +206,135 @@ func TestUnitHandlers(t *testing.T) {
 			So(body.Dataset, ShouldResemble, *mockDataset.Next)
 			So(body.Dimensions, ShouldResemble, mockVersionDetails.Dimensions)
 			So(body.VersionEtag, ShouldEqual, responseHeaders.ETag)
+			So(body.InstanceEtag, ShouldEqual, responseHeaders.ETag)
 			So(body.CollectionID, ShouldEqual, mockCollectionId)
 			So(body.CollectionState, ShouldEqual, datasetCollectionItem.State)
 			So(body.CollectionLastEditedBy, ShouldEqual, datasetCollectionItem.LastEditedBy)
 		})
+
+		Convey("returns the version etag as the instance etag without getting the instance", func() {
+			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", nil)
+			req.Header.Set("Collection-Id", mockCollectionId)
+			req.Header.Set("X-Florence-Token", mockUserAuthToken)
+			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(len(mockDatasetClient.GetInstanceCalls()), ShouldEqual, 0)
+		})
+	})
+
+	Convey("test getEditMetadataHandler inherits metadata from the latest published version", t, func() {
+
+		mockDatasetDetails := dataset.DatasetDetails{
+			ID:    "test-dataset",
+			Links: dataset.Links{LatestVersion: dataset.Link{URL: "/v1/datasets/test/editions/test/versions/1"}},
+		}
+
+		mockVersionDetails := dataset.Version{
+			ID:         "test-version",
+			Edition:    "test",
+			Version:    2,
+			State:      "edition-confirmed",
+			Dimensions: []dataset.VersionDimension{{Name: "geography"}},
+		}
+
+		mockPublishedVersion := dataset.Version{
+			ID:            "published-version",
+			Edition:       "test",
+			Version:       1,
+			State:         "published",
+			Dimensions:    []dataset.VersionDimension{{Name: "geography", Description: "geography description"}},
+			UsageNotes:    &[]dataset.UsageNote{{Title: "note", Note: "note text"}},
+			LatestChanges: []dataset.Change{{Name: "change"}},
+			Alerts:        &[]dataset.Alert{{Description: "alert"}},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{}
+
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (m datasetclient.Dataset, err error) {
+				return dataset.Dataset{Current: &mockDatasetDetails, Next: &mockDatasetDetails}, nil
+			},
+			GetVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, error) {
+				return mockPublishedVersion, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return mockVersionDetails, dataset.ResponseHeaders{}, nil
+			},
+		}
+
+		doRequestWithServiceToken := func(url, serviceAuthToken string) *httptest.ResponseRecorder {
+			req := newTestRequest("GET", url, nil, mockCollectionId, mockUserAuthToken)
+			return doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, serviceAuthToken), nil)
+		}
+
+		doRequest := func(url string) *httptest.ResponseRecorder {
+			return doRequestWithServiceToken(url, "")
+		}
+
+		Convey("copies usage notes and dimension descriptions and reports the inherited fields", func() {
+			w := doRequest("/datasets/test-dataset/editions/test/versions/2")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var body model.EditMetadata
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(*body.Version.UsageNotes, ShouldResemble, *mockPublishedVersion.UsageNotes)
+			So(body.Version.Dimensions[0].Description, ShouldEqual, "geography description")
+			So(body.Version.LatestChanges, ShouldBeEmpty)
+			So(body.Version.Alerts, ShouldBeNil)
+			So(body.Dimensions, ShouldResemble, mockPublishedVersion.Dimensions)
+			So(body.Inherited, ShouldResemble, &model.InheritedMetadata{
+				Edition: "test",
+				Version: 1,
+				Fields:  []string{"usage_notes", "dimension_descriptions"},
+			})
+		})
+
+		Convey("forwards the user and service tokens and the collection when getting the latest published version", func() {
+			w := doRequestWithServiceToken("/datasets/test-dataset/editions/test/versions/2", "service-token")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 1)
+			call := mockDatasetClient.GetVersionCalls()[0]
+			So(call.UserAuthToken, ShouldEqual, mockUserAuthToken)
+			So(call.ServiceAuthToken, ShouldEqual, "service-token")
+			So(call.CollectionID, ShouldEqual, mockCollectionId)
+			So(call.Version, ShouldEqual, "1")
+		})
+
+		Convey("copies the selected carry over fields", func() {
+			w := doRequest("/datasets/test-dataset/editions/test/versions/2?carry_over=latest_changes,alerts")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var body model.EditMetadata
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.Version.LatestChanges, ShouldResemble, mockPublishedVersion.LatestChanges)
+			So(*body.Version.Alerts, ShouldResemble, *mockPublishedVersion.Alerts)
+			So(body.Inherited.Fields, ShouldResemble, []string{"usage_notes", "dimension_descriptions", "latest_changes", "alerts"})
+		})
+
+		Convey("returns 400 for an unknown carry over field", func() {
+			w := doRequest("/datasets/test-dataset/editions/test/versions/2?carry_over=usage_notes")
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_query_parameter", Message: "invalid carry_over query parameter: usage_notes"})
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+
+		Convey("does not inherit metadata when the version is not edition-confirmed", func() {
+			mockVersionDetails.State = "associated"
+			w := doRequest("/datasets/test-dataset/editions/test/versions/2?carry_over=alerts")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var body model.EditMetadata
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.Version, ShouldResemble, mockVersionDetails)
+			So(body.Inherited, ShouldBeNil)
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 0)
+		})
 	})
 
 	Convey("test getIDsFromURL", t, func() {
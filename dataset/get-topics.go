@@ -9,38 +9,38 @@ import (
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
-// GetTopics returns a mapped list of topics
-func GetTopics(bc BabbageClient) http.HandlerFunc {
+// GetTopics returns the tree of topics and their subtopics
+func GetTopics(bc BabbageClient, serviceAuthToken string) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getTopics(w, r, bc, accessToken, collectionID, lang)
+		getTopics(w, r, bc, accessToken, serviceAuthToken, collectionID, lang)
 	})
 }
 
-func getTopics(w http.ResponseWriter, req *http.Request, bc BabbageClient, userAccessToken, collectionID, lang string) {
+func getTopics(w http.ResponseWriter, req *http.Request, bc BabbageClient, userAccessToken, serviceAuthToken, collectionID, lang string) {
 	ctx := req.Context()
 
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
 	log.Info(ctx, "calling get topics")
 
-	topics, err := bc.GetTopics(ctx, userAccessToken)
+	taxonomy, err := bc.GetTopicTree(ctx, userAccessToken, serviceAuthToken)
 	if err != nil {
 		log.Error(ctx, "error getting topics", err)
-		http.Error(w, "error getting topics", http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, babbageService, "error getting topics", err)
 		return
 	}
 
-	mapped := mapper.Topics(topics)
+	mapped := mapper.TopicTree(taxonomy)
 
 	b, err := json.Marshal(mapped)
 	if err != nil {
 		log.Error(ctx, "error marshalling response to json", err)
-		http.Error(w, "error marshalling response to json", http.StatusInternalServerError)
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
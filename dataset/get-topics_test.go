@@ -10,39 +10,33 @@ import (
 	"github.com/gorilla/mux"
 
 	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestUnitGetAllTopics(t *testing.T) {
 
-	mockTopics := babbageclient.TopicsResult{
-		Topics: babbageclient.Topic{
-			Results: []babbageclient.Result{{
-				Description: babbageclient.Description{
-					Title: "test 1",
-				},
-				URI:  "/test/uri/1",
-				Type: "page",
+	mockTaxonomy := []babbageclient.TaxonomyNode{
+		{
+			ID:    "economy",
+			Title: "Economy",
+			URI:   "/economy",
+			Type:  "taxonomy_landing_page",
+			Children: []babbageclient.TaxonomyNode{
+				{ID: "inflationandpriceindices", Title: "Inflation and price indices", URI: "/economy/inflationandpriceindices", Type: "product_page"},
 			},
-				{
-					Description: babbageclient.Description{
-						Title: "test 2",
-					},
-					URI:  "/test/uri/2",
-					Type: "page",
-				}},
 		},
 	}
 
-	expectedSuccessResponse := "[{\"title\":\"test 1\"},{\"title\":\"test 2\"}]"
+	expectedSuccessResponse := `[{"id":"economy","title":"Economy","uri":"/economy","subtopics":[{"id":"inflationandpriceindices","title":"Inflation and price indices","uri":"/economy/inflationandpriceindices","subtopics":[]}]}]`
 
 	Convey("test getTopics", t, func() {
 		Convey("on success", func() {
 
 			mockBabbageClient := &BabbageClientMock{
-				GetTopicsFunc: func(ctx context.Context, userAuthToken string) (babbageclient.TopicsResult, error) {
-					return mockTopics, nil
+				GetTopicTreeFunc: func(ctx context.Context, userAuthToken, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error) {
+					return mockTaxonomy, nil
 				},
 			}
 
@@ -51,7 +45,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient))
+			router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient, ""))
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
 				So(rec.Code, ShouldEqual, http.StatusOK)
@@ -67,8 +61,8 @@ func TestUnitGetAllTopics(t *testing.T) {
 		Convey("errors if no headers are passed", func() {
 
 			mockBabbageClient := &BabbageClientMock{
-				GetTopicsFunc: func(ctx context.Context, userAuthToken string) (babbageclient.TopicsResult, error) {
-					return babbageclient.TopicsResult{}, nil
+				GetTopicTreeFunc: func(ctx context.Context, userAuthToken, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error) {
+					return nil, nil
 				},
 			}
 
@@ -77,7 +71,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient))
+				router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient, ""))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -87,7 +81,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no collection ID header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 				})
 			})
 
@@ -96,7 +90,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient))
+				router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient, ""))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -106,7 +100,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no user access token header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 				})
 			})
 		})
@@ -114,8 +108,8 @@ func TestUnitGetAllTopics(t *testing.T) {
 		Convey("handles error from babbage client", func() {
 
 			mockBabbageClient := &BabbageClientMock{
-				GetTopicsFunc: func(ctx context.Context, userAuthToken string) (babbageclient.TopicsResult, error) {
-					return babbageclient.TopicsResult{}, errors.New("test babbage API error")
+				GetTopicTreeFunc: func(ctx context.Context, userAuthToken, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error) {
+					return nil, errors.New("test babbage API error")
 				},
 			}
 
@@ -124,7 +118,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient))
+			router.Path("/datasets/123/create").HandlerFunc(GetTopics(mockBabbageClient, ""))
 
 			Convey("returns 500 response", func() {
 				router.ServeHTTP(rec, req)
@@ -134,7 +128,7 @@ func TestUnitGetAllTopics(t *testing.T) {
 			Convey("returns error body", func() {
 				router.ServeHTTP(rec, req)
 				response := rec.Body.String()
-				So(response, ShouldResemble, "error getting topics\n")
+				So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error getting topics", UpstreamService: "babbage"})
 			})
 
 		})
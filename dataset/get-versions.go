@@ -11,64 +11,96 @@ import (
 	"github.com/gorilla/mux"
 )
 
-// GetVersions returns a mapped list of all versions
-func GetVersions(dc DatasetClient, batchSize, maxWorkers int) http.HandlerFunc {
+var (
+	versionSorts  = map[string]bool{mapper.SortByVersion: true, mapper.SortByReleaseDate: true}
+	versionOrders = map[string]bool{mapper.OrderAsc: true, mapper.OrderDesc: true}
+)
+
+// GetVersions returns a mapped list of all versions, optionally filtered by state and sorted by version or release date
+func GetVersions(dc DatasetClient, serviceAuthToken string, batchSize, maxWorkers int) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getVersions(w, r, dc, accessToken, collectionID, lang, batchSize, maxWorkers)
+		getVersions(w, r, dc, accessToken, serviceAuthToken, collectionID, lang, batchSize, maxWorkers)
 	})
 }
 
-func getVersions(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, collectionID, lang string, batchSize, maxWorkers int) {
+func getVersions(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, lang string, batchSize, maxWorkers int) {
 	ctx := req.Context()
 
 	vars := mux.Vars(req)
 	datasetID := vars["datasetID"]
 	editionID := vars["editionID"]
 
-	logInfo := map[string]interface{}{
-		"datasetID": datasetID,
-		"edition":   editionID,
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
 	}
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	states, err := getListQueryParam(req, "state", mapper.VersionStates)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
 		return
 	}
 
+	sortBy, err := getEnumQueryParam(req, "sort", mapper.SortByVersion, versionSorts)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
+	order, err := getEnumQueryParam(req, "order", mapper.OrderDesc, versionOrders)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   editionID,
+		"state":     states,
+		"sort":      sortBy,
+		"order":     order,
+	}
+
 	log.Info(ctx, "calling get versions", log.Data(logInfo))
 
-	dataset, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, "", collectionID, datasetID)
+	dataset, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
 	if err != nil {
 		errMsg := fmt.Sprintf("error getting dataset from dataset API: %v", err.Error())
 		log.Error(ctx, "error getting dataset from dataset API", err, log.Data(logInfo))
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, errMsg, err)
 		return
 	}
 
-	edition, err := dc.GetEdition(ctx, userAccessToken, "", collectionID, datasetID, editionID)
+	edition, err := dc.GetEdition(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, editionID)
 	if err != nil {
 		errMsg := fmt.Sprintf("error getting edition from dataset API: %v", err.Error())
 		log.Error(ctx, "error getting edition from dataset API", err, log.Data(logInfo))
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, errMsg, err)
 		return
 	}
 
-	versions, err := dc.GetVersionsInBatches(ctx, userAccessToken, "", "", collectionID, datasetID, editionID, batchSize, maxWorkers)
+	versions, err := dc.GetVersionsInBatches(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, editionID, batchSize, maxWorkers)
 	if err != nil {
 		errMsg := fmt.Sprintf("error getting all versions from dataset API: %v", err.Error())
 		log.Error(ctx, "error getting all versions from dataset API", err, log.Data(logInfo))
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		writeUpstreamErrorResponse(w, req, datasetAPIService, errMsg, err)
 		return
 	}
 
-	mapped := mapper.AllVersions(ctx, dataset, edition, versions)
+	filtered := mapper.FilterVersions(versions, states)
+	mapped := mapper.AllVersions(ctx, dataset, edition, filtered)
+	mapper.SortVersions(mapped.Versions, filtered.Items, sortBy, order)
+	mapped.StateCounts = mapper.VersionStateCounts(versions)
 
 	b, err := json.Marshal(mapped)
 	if err != nil {
 		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
-		http.Error(w, "error marshalling response to json", http.StatusInternalServerError)
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -2,6 +2,7 @@ package dataset
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/gorilla/mux"
 
 	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -35,18 +37,21 @@ func TestUnitGetVersions(t *testing.T) {
 
 	mockedVersionsResponse := []datasetclient.Version{
 		{
-			ID:         "version-1",
-			InstanceID: "instance-001",
-			Version:    1,
+			ID:          "version-1",
+			InstanceID:  "instance-001",
+			Version:     1,
+			State:       "published",
+			ReleaseDate: "2020-11-07T00:00:00.000Z",
 		},
 		{
 			ID:         "version-2",
 			InstanceID: "instance-002",
 			Version:    2,
+			State:      "associated",
 		},
 	}
 
-	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"edition_name\":\"edition-1\",\"versions\":[{\"id\":\"version-2\",\"title\":\"Version: 2\",\"version\":2,\"release_date\":\"\",\"state\":\"\"},{\"id\":\"version-1\",\"title\":\"Version: 1\",\"version\":1,\"release_date\":\"\",\"state\":\"\"}]}"
+	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"edition_name\":\"edition-1\",\"versions\":[{\"id\":\"version-2\",\"title\":\"Version: 2\",\"version\":2,\"release_date\":\"\",\"state\":\"associated\"},{\"id\":\"version-1\",\"title\":\"Version: 1 (published)\",\"version\":1,\"release_date\":\"07 November 2020\",\"state\":\"published\"}],\"state_counts\":{\"associated\":1,\"created\":0,\"edition-confirmed\":0,\"published\":1}}"
 
 	Convey("test getAllVersions", t, func() {
 
@@ -69,7 +74,7 @@ func TestUnitGetVersions(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, verionsBatchSize, versionsMaxWorkers))
+			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, "", verionsBatchSize, versionsMaxWorkers))
 
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
@@ -90,7 +95,7 @@ func TestUnitGetVersions(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, verionsBatchSize, versionsMaxWorkers))
+				router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, "", verionsBatchSize, versionsMaxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -100,7 +105,7 @@ func TestUnitGetVersions(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no collection ID header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 				})
 			})
 
@@ -110,7 +115,7 @@ func TestUnitGetVersions(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, verionsBatchSize, versionsMaxWorkers))
+				router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, "", verionsBatchSize, versionsMaxWorkers))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -120,7 +125,7 @@ func TestUnitGetVersions(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no user access token header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 				})
 			})
 		})
@@ -144,7 +149,7 @@ func TestUnitGetVersions(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, verionsBatchSize, versionsMaxWorkers))
+			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, "", verionsBatchSize, versionsMaxWorkers))
 
 			Convey("returns 500 response", func() {
 				router.ServeHTTP(rec, req)
@@ -154,9 +159,59 @@ func TestUnitGetVersions(t *testing.T) {
 			Convey("returns error body", func() {
 				router.ServeHTTP(rec, req)
 				response := rec.Body.String()
-				So(response, ShouldResemble, "error getting all versions from dataset API: test dataset API error\n")
+				So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error getting all versions from dataset API: test dataset API error", UpstreamService: "dataset-api"})
 			})
 
 		})
+
+		Convey("filters and sorts versions using the query parameters", func() {
+			reqURL := fmt.Sprintf("/datasets/%v/editions/%v/versions", datasetID, editionID)
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, "", verionsBatchSize, versionsMaxWorkers))
+
+			doRequest := func(query string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest("GET", reqURL+query, nil)
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec
+			}
+
+			Convey("returns the versions in the given state with counts for every state", func() {
+				rec := doRequest("?state=associated")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var response model.VersionsPage
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(len(response.Versions), ShouldEqual, 1)
+				So(response.Versions[0].ID, ShouldEqual, "version-2")
+				So(response.StateCounts["associated"], ShouldEqual, 1)
+				So(response.StateCounts["published"], ShouldEqual, 1)
+			})
+
+			Convey("returns the versions in the requested order", func() {
+				rec := doRequest("?sort=version&order=asc")
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var response model.VersionsPage
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Versions[0].ID, ShouldEqual, "version-1")
+				So(response.Versions[1].ID, ShouldEqual, "version-2")
+			})
+
+			Convey("returns 400 for invalid query parameters", func() {
+				for query, message := range map[string]string{
+					"?state=deleted": "invalid state query parameter: deleted",
+					"?sort=title":    "invalid sort query parameter: title",
+					"?order=up":      "invalid order query parameter: up",
+				} {
+					rec := doRequest(query)
+					So(rec.Code, ShouldEqual, http.StatusBadRequest)
+					So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_query_parameter", Message: message})
+				}
+				So(len(mockDatasetClient.GetVersionsInBatchesCalls()), ShouldEqual, 0)
+			})
+		})
 	})
 }
@@ -0,0 +1,205 @@
+package dataset
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetLegacyMetadata returns the dataset and version metadata in the format used by the legacy Florence edit screens
+func GetLegacyMetadata(dc DatasetClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getLegacyMetadata(w, r, dc, accessToken, serviceAuthToken, collectionID)
+	})
+}
+
+func getLegacyMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
+		return
+	}
+	if d.Next == nil {
+		log.Warn(ctx, "getLegacyMetadata endpoint: dataset has no unpublished state", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusNotFound, errCodeNotFound, "dataset has no unpublished state")
+		return
+	}
+
+	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+
+	metadata, err := mapper.EditDatasetVersionMetaData(*d.Next, v)
+	if err != nil {
+		log.Error(ctx, "failed mapping legacy metadata", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "failed mapping legacy metadata")
+		return
+	}
+	metadata.VersionEtag = headers.ETag
+
+	writeLegacyMetadata(w, req, metadata, logInfo)
+
+	log.Info(ctx, "get legacy metadata: request successful", log.Data(logInfo))
+}
+
+// PutLegacyMetadata updates the dataset and version from the metadata form used by the legacy Florence edit screens
+func PutLegacyMetadata(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putLegacyMetadata(w, r, dc, zc, accessToken, serviceAuthToken, collectionID)
+	})
+}
+
+func putLegacyMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "putLegacyMetadata endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var body model.EditVersionMetaData
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "putLegacyMetadata endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if body.VersionEtag == "" {
+		log.Warn(ctx, "putLegacyMetadata endpoint: missing version etag", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusPreconditionRequired, errCodePreconditionRequired, "version_etag is required")
+		return
+	}
+
+	// the legacy form only holds some of the dataset and version fields, so it is applied to the current state
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
+		return
+	}
+	if d.Next == nil {
+		log.Warn(ctx, "putLegacyMetadata endpoint: dataset has no unpublished state", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusNotFound, errCodeNotFound, "dataset has no unpublished state")
+		return
+	}
+
+	v, err := dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+
+	updatedDataset, updatedVersion, err := mapper.LegacyMetadata(body.MetaData, *d.Next, v)
+	if err != nil {
+		log.Error(ctx, "putLegacyMetadata endpoint: error mapping body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	if updatedDataset.Contacts != nil {
+		if fieldErrors := validateContacts("/meta_data/contacts", *updatedDataset.Contacts); len(fieldErrors) > 0 {
+			writeValidationError(w, req, fieldErrors, logInfo)
+			return
+		}
+	}
+
+	collectionState := body.CollectionState
+	if collectionState == "" {
+		collectionState, err = collectionDatasetState(ctx, zc, userAccessToken, collectionID, datasetID)
+		if err != nil {
+			log.Error(ctx, "failed Get collection details", err, log.Data(logInfo))
+			writeUpstreamErrorResponse(w, req, zebedeeService, "failed to get collection details", err)
+			return
+		}
+	}
+
+	// the dataset and version are written in a single call, guarded by the version etag the form was loaded with. The form
+	// does not send the values it was loaded with, so a conflict returns the current metadata rather than the fields that
+	// were changed by someone else
+	editableMetadata := mapper.PutMetadata(model.EditMetadata{Dataset: updatedDataset, Version: updatedVersion})
+
+	newVersionEtag, err := writeEditableMetadata(ctx, dc, zc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, editableMetadata, body.VersionEtag, collectionState)
+	if err != nil {
+		writeEditableMetadataError(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, nil, editableMetadata, body.VersionEtag, err, logInfo)
+		return
+	}
+
+	metadata, err := mapper.EditDatasetVersionMetaData(updatedDataset, updatedVersion)
+	if err != nil {
+		log.Error(ctx, "failed mapping legacy metadata", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "failed mapping legacy metadata")
+		return
+	}
+	metadata.VersionEtag = newVersionEtag
+
+	writeLegacyMetadata(w, req, metadata, logInfo)
+
+	log.Info(ctx, "put legacy metadata: request successful", log.Data(logInfo))
+}
+
+func writeLegacyMetadata(w http.ResponseWriter, req *http.Request, metadata model.EditVersionMetaData, logInfo map[string]interface{}) {
+	ctx := req.Context()
+
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		log.Error(ctx, "failed marshalling page into bytes", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "failed marshalling page into bytes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "failed to write bytes for http response", err, log.Data(logInfo))
+	}
+}
@@ -0,0 +1,288 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLegacyMetadata(t *testing.T) {
+	t.Parallel()
+
+	const mockCollectionID = "testcollection"
+	const mockUserAuthToken = "testuser"
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/legacy-metadata"
+	const reqURL = "/datasets/test-dataset/editions/test-edition/versions/2/legacy-metadata"
+
+	mockDatasetDetails := datasetclient.DatasetDetails{
+		ID:       "test-dataset",
+		Title:    "Test title",
+		Keywords: &[]string{"one", "two"},
+		Contacts: &[]datasetclient.Contact{{Name: "contact", Email: "contact@ons.gov.uk"}},
+	}
+
+	mockVersionDetails := datasetclient.Version{
+		ID:           "test-version",
+		Edition:      "test-edition",
+		Version:      2,
+		CollectionID: mockCollectionID,
+		UsageNotes:   &[]datasetclient.UsageNote{{Title: "note", Note: "note text"}},
+	}
+
+	newRequest := func(method string, body interface{}) *http.Request {
+		var b []byte
+		if body != nil {
+			b, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, reqURL, bytes.NewBuffer(b))
+		req.Header.Set("Collection-Id", mockCollectionID)
+		req.Header.Set("X-Florence-Token", mockUserAuthToken)
+		return req
+	}
+
+	Convey("test getLegacyMetadata", t, func() {
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Next: &mockDatasetDetails}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return mockVersionDetails, datasetclient.ResponseHeaders{ETag: "version-etag"}, nil
+			},
+		}
+
+		Convey("returns the legacy metadata model", func() {
+			w := doTestRequest(target, newRequest("GET", nil), GetLegacyMetadata(mockDatasetClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			var body model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.InstanceID, ShouldEqual, "test-version")
+			So(body.Collection, ShouldEqual, mockCollectionID)
+			So(body.MetaData.Title, ShouldEqual, "Test title")
+			So(body.MetaData.Keywords, ShouldEqual, "one, two")
+			So(body.MetaData.ContactEmail, ShouldEqual, "contact@ons.gov.uk")
+			So(body.MetaData.UsageNotes[0].Title, ShouldEqual, "note")
+			So(body.VersionEtag, ShouldEqual, "version-etag")
+		})
+
+		Convey("returns an empty contact when the dataset has an empty contacts list", func() {
+			noContacts := mockDatasetDetails
+			noContacts.Contacts = &[]datasetclient.Contact{}
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Next: &noContacts}, nil
+			}
+			w := doTestRequest(target, newRequest("GET", nil), GetLegacyMetadata(mockDatasetClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			var body model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.MetaData.ContactName, ShouldBeEmpty)
+			So(body.MetaData.ContactEmail, ShouldBeEmpty)
+		})
+
+		Convey("returns 404 when the dataset has no unpublished state", func() {
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Current: &mockDatasetDetails}, nil
+			}
+			w := doTestRequest(target, newRequest("GET", nil), GetLegacyMetadata(mockDatasetClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{Code: "not_found", Message: "dataset has no unpublished state"})
+		})
+
+		Convey("returns 500 when a version alert can't be mapped", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return datasetclient.Version{Alerts: &[]datasetclient.Alert{{Date: "invalid"}}}, datasetclient.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest("GET", nil), GetLegacyMetadata(mockDatasetClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(w.Body.String()).Code, ShouldEqual, "internal_error")
+		})
+
+		Convey("handles error from dataset client", func() {
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{}, errors.New("test dataset API error")
+			}
+			w := doTestRequest(target, newRequest("GET", nil), GetLegacyMetadata(mockDatasetClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "failed to get dataset details", UpstreamService: "dataset-api"})
+		})
+	})
+
+	Convey("test putLegacyMetadata", t, func() {
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Next: &mockDatasetDetails}, nil
+			},
+			GetVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, error) {
+				return mockVersionDetails, nil
+			},
+			PutMetadataFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, metadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "new-version-etag", nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID, Datasets: []zebedeeclient.CollectionItem{{ID: "test-dataset", State: "Complete"}}}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+
+		form := model.EditVersionMetaData{
+			MetaData: model.MetaData{
+				Title:         "New title",
+				Keywords:      "three",
+				ContactName:   "contact",
+				ContactEmail:  "contact@ons.gov.uk",
+				UsageNotes:    []model.UsageNote{{Title: "new note", Note: "new note text"}},
+				LatestChanges: []model.LatestChanges{{Title: "change", Description: "change description"}},
+			},
+			VersionEtag: "version-etag",
+		}
+
+		Convey("updates the dataset and version from the legacy form, guarded by the version etag", func() {
+			w := doTestRequest(target, newRequest("PUT", form), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 1)
+			call := mockDatasetClient.PutMetadataCalls()[0]
+			So(call.VersionEtag, ShouldEqual, "version-etag")
+			So(call.Metadata.Title, ShouldEqual, "New title")
+			So(call.Metadata.Keywords, ShouldResemble, []string{"three"})
+			So(*call.Metadata.UsageNotes, ShouldResemble, []datasetclient.UsageNote{{Title: "new note", Note: "new note text"}})
+			So(*call.Metadata.LatestChanges, ShouldResemble, []datasetclient.Change{{Name: "change", Description: "change description"}})
+
+			var body model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.MetaData.Title, ShouldEqual, "New title")
+
+			Convey("and returns the new version etag so that the form can be saved again", func() {
+				So(body.VersionEtag, ShouldEqual, "new-version-etag")
+			})
+		})
+
+		Convey("keeps the state of the dataset in the collection", func() {
+			w := doTestRequest(target, newRequest("PUT", form), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "Complete")
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Complete")
+		})
+
+		Convey("uses the collection state from the form when it is set", func() {
+			stateForm := form
+			stateForm.CollectionState = "InProgress"
+			w := doTestRequest(target, newRequest("PUT", stateForm), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+			So(len(mockZebedeeClient.GetCollectionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 428 when the version etag is missing", func() {
+			noEtagForm := form
+			noEtagForm.VersionEtag = ""
+			w := doTestRequest(target, newRequest("PUT", noEtagForm), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusPreconditionRequired)
+			So(decodeErrorResponse(w.Body.String()), ShouldResemble, model.ErrorResponse{Code: "precondition_required", Message: "version_etag is required"})
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 404 when the dataset has no unpublished state", func() {
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{Current: &mockDatasetDetails}, nil
+			}
+			w := doTestRequest(target, newRequest("PUT", form), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 400 when a notice date is invalid", func() {
+			invalidForm := form
+			invalidForm.MetaData.Notices = []model.Notice{{Date: "invalid"}}
+			w := doTestRequest(target, newRequest("PUT", invalidForm), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(w.Body.String()).Code, ShouldEqual, "invalid_request_body")
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 0)
+		})
+
+		Convey("updates all contacts from the contacts list", func() {
+			contactsForm := form
+			contactsForm.MetaData.Contacts = []model.Contact{
+				{Name: "first", Email: "first@ons.gov.uk"},
+				{Name: "second", Telephone: "01633 456789"},
+			}
+			w := doTestRequest(target, newRequest("PUT", contactsForm), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(mockDatasetClient.PutMetadataCalls()[0].Metadata.Contacts, ShouldResemble, []datasetclient.Contact{
+				{Name: "first", Email: "first@ons.gov.uk"},
+				{Name: "second", Telephone: "01633 456789"},
+			})
+
+			var body model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(len(body.MetaData.Contacts), ShouldEqual, 2)
+			So(body.MetaData.Contacts[1].Telephone, ShouldEqual, "01633 456789")
+		})
+
+		Convey("returns 422 when a contact email is invalid", func() {
+			contactsForm := form
+			contactsForm.MetaData.Contacts = []model.Contact{{Name: "first", Email: "not-an-email"}}
+			w := doTestRequest(target, newRequest("PUT", contactsForm), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+			var response model.ValidationError
+			So(json.Unmarshal(w.Body.Bytes(), &response), ShouldBeNil)
+			So(response.Code, ShouldEqual, "validation_failed")
+			So(response.Errors, ShouldResemble, []model.FieldError{{Pointer: "/meta_data/contacts/0/email", Message: "must be a valid email address"}})
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns the current metadata when the version etag is stale", func() {
+			changedVersion := mockVersionDetails
+			changedVersion.ReleaseDate = "2021-01-01T00:00:00.000Z"
+			mockDatasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, metadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "", testStatusError{http.StatusPreconditionFailed}
+			}
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return changedVersion, datasetclient.ResponseHeaders{ETag: "current-etag"}, nil
+			}
+			w := doTestRequest(target, newRequest("PUT", form), PutLegacyMetadata(mockDatasetClient, mockZebedeeClient, ""), nil)
+
+			So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+			var conflict model.MetadataConflict
+			So(json.Unmarshal(w.Body.Bytes(), &conflict), ShouldBeNil)
+			So(conflict.CurrentEtag, ShouldEqual, "current-etag")
+			So(conflict.Conflicts, ShouldBeNil)
+			So(conflict.Current, ShouldNotBeNil)
+			So(conflict.Current.ReleaseDate, ShouldEqual, "2021-01-01T00:00:00.000Z")
+			So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+		})
+	})
+}
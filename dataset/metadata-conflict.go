@@ -0,0 +1,92 @@
+package dataset
+
+import (
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// mayBeETagConflict reports whether the upstream error has a status returned for a stale ETag
+func mayBeETagConflict(err error) bool {
+	status := upstreamStatusCode(err)
+	return status == http.StatusConflict || status == http.StatusPreconditionFailed
+}
+
+// isETagConflict reports whether the upstream error was caused by a stale ETag. A 412 is always an ETag mismatch, but the
+// dataset API also returns a 409 for other conflicts, so a 409 is only treated as one if the sent ETag is no longer current
+func isETagConflict(err error, sentETag, currentETag string) bool {
+	switch upstreamStatusCode(err) {
+	case http.StatusPreconditionFailed:
+		return true
+	case http.StatusConflict:
+		return sentETag != "" && sentETag != currentETag
+	default:
+		return false
+	}
+}
+
+// writeMetadataConflict writes an optimistic locking error response containing the current version ETag and the
+// editable metadata fields that have been changed by someone else since the client loaded them. Those fields can only be
+// worked out from the metadata the client loaded, so if it is nil the current metadata is returned instead. If the error
+// was not caused by a stale ETag it is written as an upstream error
+func writeMetadataConflict(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version string, loaded *datasetclient.EditableMetadata, submitted datasetclient.EditableMetadata, sentETag string, conflictErr error) {
+	ctx := req.Context()
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed to get current version after metadata conflict", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating metadata", conflictErr)
+		return
+	}
+
+	if !isETagConflict(conflictErr, sentETag, headers.ETag) {
+		log.Error(ctx, "error updating metadata", conflictErr, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating metadata", conflictErr)
+		return
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil || d.Next == nil {
+		log.Error(ctx, "failed to get current dataset after metadata conflict", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating metadata", conflictErr)
+		return
+	}
+
+	current := mapper.PutMetadata(model.EditMetadata{Dataset: *d.Next, Version: v})
+
+	errResponse, status := newUpstreamErrorResponse(req, datasetAPIService, "metadata has been changed by another user since it was loaded", conflictErr)
+	conflict := model.MetadataConflict{
+		ErrorResponse: errResponse,
+		CurrentEtag:   headers.ETag,
+	}
+	if loaded != nil {
+		conflict.Conflicts = mapper.EditableMetadataConflicts(*loaded, submitted, current)
+	} else {
+		conflict.Current = &current
+	}
+
+	log.Warn(ctx, "metadata update rejected due to a stale version etag", log.Data(logInfo))
+	writeJSONError(w, req, status, conflict)
+}
+
+// writeInstanceConflict writes an optimistic locking error response for an instance that has changed since its ETag was read,
+// containing the current instance ETag and the dimensions if they differ from the submitted ones
+func writeInstanceConflict(w http.ResponseWriter, req *http.Request, instanceID, currentETag string, submitted, current []datasetclient.VersionDimension) {
+	conflict := model.MetadataConflict{
+		ErrorResponse: newErrorResponse(req, errCodeConflict, "dimensions have been changed by another user since they were loaded"),
+		CurrentEtag:   currentETag,
+		Conflicts:     mapper.DimensionConflicts(submitted, current),
+	}
+
+	log.Warn(req.Context(), "metadata update rejected due to a stale instance etag", log.Data{"instanceID": instanceID})
+	writeJSONError(w, req, http.StatusConflict, conflict)
+}
@@ -0,0 +1,60 @@
+package dataset
+
+import (
+	"encoding/json"
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const dryRunQueryParam = "dry_run"
+
+// writeMetadataDryRun writes the changes a metadata update would make to the current dataset and version, without writing anything
+func writeMetadataDryRun(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version string, planned datasetclient.EditableMetadata, logInfo map[string]interface{}) {
+	ctx := req.Context()
+
+	v, headers, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
+		return
+	}
+
+	currentDataset := datasetclient.DatasetDetails{}
+	if d.Next != nil {
+		currentDataset = *d.Next
+	}
+	current := mapper.PutMetadata(model.EditMetadata{Dataset: currentDataset, Version: v})
+
+	dryRun := model.MetadataDryRun{
+		DryRun:      true,
+		VersionEtag: headers.ETag,
+		Changes:     mapper.EditableMetadataChanges(current, planned),
+	}
+
+	b, err := json.Marshal(dryRun)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "metadata dry run: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,323 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const (
+	stepUpdateDataset          = "update dataset"
+	stepUpdateVersion          = "update version"
+	stepUpdateDimensions       = "update dimensions"
+	stepAddDatasetToCollection = "add dataset to collection"
+	stepAddVersionToCollection = "add version to collection"
+)
+
+var (
+	errStepNotRevertible = errors.New("step cannot be reverted")
+	errNoNextDataset     = errors.New("dataset has no unpublished state")
+)
+
+// metadataSnapshot holds the state of a dataset, version and instance before they are updated, along with the ETag
+// that the version and its instance share
+type metadataSnapshot struct {
+	dataset  datasetclient.DatasetDetails
+	version  datasetclient.Version
+	instance datasetclient.Instance
+	eTag     string
+}
+
+// metadataUpdate records the outcome of each step of a multi-call metadata update, along with the dataset and version
+// that are written, which are needed to revert them. The version and its instance share an ETag that changes on every
+// write to either, so eTag holds the one returned by the latest write
+type metadataUpdate struct {
+	steps   []string
+	dataset datasetclient.DatasetDetails
+	version datasetclient.Version
+	eTag    string
+}
+
+// getMetadataSnapshot gets the current dataset, version and instance so that they can be restored if an update fails
+func getMetadataSnapshot(ctx context.Context, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, instanceID string) (metadataSnapshot, error) {
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		return metadataSnapshot{}, err
+	}
+	if d.Next == nil {
+		return metadataSnapshot{}, errNoNextDataset
+	}
+
+	v, err := dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		return metadataSnapshot{}, err
+	}
+
+	i, eTag, err := dc.GetInstance(ctx, userAccessToken, serviceAuthToken, collectionID, instanceID, "")
+	if err != nil {
+		return metadataSnapshot{}, err
+	}
+
+	snapshot := metadataSnapshot{
+		dataset:  *d.Next,
+		version:  v,
+		instance: i,
+		eTag:     eTag,
+	}
+	return snapshot, nil
+}
+
+// succeeded records that a step completed
+func (u *metadataUpdate) succeeded(step string) {
+	u.steps = append(u.steps, step)
+}
+
+// fail reverts the completed steps, in reverse order, and writes an error response listing which steps succeeded,
+// which step failed and which steps were rolled back. A step is partly rolled back if some of the fields it set
+// could not be cleared, and those fields are listed as not restored
+func (u *metadataUpdate) fail(w http.ResponseWriter, req *http.Request, dc DatasetClient, snapshot metadataSnapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, failedStep string, stepErr error) {
+	ctx := req.Context()
+
+	service := datasetAPIService
+	if failedStep == stepAddDatasetToCollection || failedStep == stepAddVersionToCollection {
+		service = zebedeeService
+	}
+	errResponse, status := newUpstreamErrorResponse(req, service, "error updating metadata: "+failedStep+" failed", stepErr)
+
+	updateErr := model.UpdateError{
+		ErrorResponse:    errResponse,
+		Succeeded:        append([]string{}, u.steps...),
+		Failed:           failedStep,
+		RolledBack:       []string{},
+		PartlyRolledBack: []string{},
+		NotRestored:      []string{},
+		RollbackFailed:   []string{},
+	}
+
+	for i := len(u.steps) - 1; i >= 0; i-- {
+		step := u.steps[i]
+		notRestored, err := u.restore(ctx, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, step)
+		if err == errStepNotRevertible {
+			continue
+		}
+		if err != nil {
+			log.Error(ctx, "error rolling back metadata update", err, log.Data{"datasetID": datasetID, "edition": edition, "version": version, "step": step})
+			updateErr.RollbackFailed = append(updateErr.RollbackFailed, step)
+			continue
+		}
+		if len(notRestored) > 0 {
+			log.Warn(ctx, "metadata update partly rolled back", log.Data{"datasetID": datasetID, "edition": edition, "version": version, "step": step, "not_restored": notRestored})
+			updateErr.PartlyRolledBack = append(updateErr.PartlyRolledBack, step)
+			updateErr.NotRestored = append(updateErr.NotRestored, notRestored...)
+			continue
+		}
+		updateErr.RolledBack = append(updateErr.RolledBack, step)
+	}
+
+	writeJSONError(w, req, status, updateErr)
+}
+
+// restore writes back the snapshot state for the dataset API resource changed by the given step, returning the names of
+// any fields that could not be restored. Adding content to a zebedee collection is not reverted, as the dataset API
+// state it points at is restored
+func (u *metadataUpdate) restore(ctx context.Context, dc DatasetClient, s metadataSnapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, step string) ([]string, error) {
+	switch step {
+	case stepUpdateDataset:
+		d, notRestored := restorableDataset(s.dataset, u.dataset)
+		return notRestored, dc.PutDataset(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, d)
+	case stepUpdateVersion:
+		v, notRestored := restorableVersion(s.version, u.version)
+		eTag, err := dc.PutVersion(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, v, u.eTag)
+		if err != nil {
+			return nil, err
+		}
+		u.eTag = eTag
+		return notRestored, nil
+	case stepUpdateDimensions:
+		instance := datasetclient.UpdateInstance{
+			InstanceID: s.instance.ID,
+			Dimensions: s.instance.Dimensions,
+		}
+		eTag, err := dc.PutInstance(ctx, userAccessToken, serviceAuthToken, collectionID, s.instance.ID, instance, u.eTag)
+		if err != nil {
+			return nil, err
+		}
+		u.eTag = eTag
+		return nil, nil
+	default:
+		return nil, errStepNotRevertible
+	}
+}
+
+// restorableDataset returns the snapshot dataset to write back over the updated one. Empty lists are sent for lists
+// that the update set, so that they are cleared. Other fields are omitted from the request when empty, so any that
+// were empty in the snapshot but set by the update cannot be cleared and their names are returned
+func restorableDataset(snapshot, updated datasetclient.DatasetDetails) (datasetclient.DatasetDetails, []string) {
+	d := snapshot
+	if d.Contacts == nil && updated.Contacts != nil {
+		d.Contacts = &[]datasetclient.Contact{}
+	}
+	if d.Keywords == nil && updated.Keywords != nil {
+		d.Keywords = &[]string{}
+	}
+	if d.Methodologies == nil && updated.Methodologies != nil {
+		d.Methodologies = &[]datasetclient.Methodology{}
+	}
+	if d.Publications == nil && updated.Publications != nil {
+		d.Publications = &[]datasetclient.Publication{}
+	}
+	if d.RelatedDatasets == nil && updated.RelatedDatasets != nil {
+		d.RelatedDatasets = &[]datasetclient.RelatedDataset{}
+	}
+	if d.UsageNotes == nil && updated.UsageNotes != nil {
+		d.UsageNotes = &[]datasetclient.UsageNote{}
+	}
+	if d.RelatedContent == nil && updated.RelatedContent != nil {
+		d.RelatedContent = &[]datasetclient.GeneralDetails{}
+	}
+
+	return d, notRestoredFields(d, updated)
+}
+
+// restorableVersion returns the snapshot version to write back over the updated one. As for the dataset, empty lists are
+// sent for the lists that the update set, and the names of other fields that were set by the update but cannot be
+// cleared are returned
+func restorableVersion(snapshot, updated datasetclient.Version) (datasetclient.Version, []string) {
+	v := snapshot
+	if v.Alerts == nil && updated.Alerts != nil {
+		v.Alerts = &[]datasetclient.Alert{}
+	}
+	if v.LatestChanges == nil && updated.LatestChanges != nil {
+		v.LatestChanges = []datasetclient.Change{}
+	}
+	if v.UsageNotes == nil && updated.UsageNotes != nil {
+		v.UsageNotes = &[]datasetclient.UsageNote{}
+	}
+
+	return v, notRestoredFields(v, updated)
+}
+
+// notRestoredFields returns the names of the fields set in the update that are not set in the restored resource. The
+// dataset API only changes the fields that are set in a request, so these keep the value the update gave them
+func notRestoredFields(restored, updated interface{}) []string {
+	restoredFields, err := fieldsSet(restored)
+	if err != nil {
+		return nil
+	}
+	updatedFields, err := fieldsSet(updated)
+	if err != nil {
+		return nil
+	}
+
+	var notRestored []string
+	for field := range updatedFields {
+		if !restoredFields[field] {
+			notRestored = append(notRestored, field)
+		}
+	}
+	sort.Strings(notRestored)
+	return notRestored
+}
+
+// fieldsSet returns the JSON fields that are sent to the dataset API with a value, so are not ignored by it
+func fieldsSet(v interface{}) (map[string]bool, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(fields))
+	for field, value := range fields {
+		if string(value) != "null" && string(value) != `""` {
+			set[field] = true
+		}
+	}
+	return set, nil
+}
+
+// metadataWriteError records the upstream service call that failed while writing editable metadata
+type metadataWriteError struct {
+	service string
+	message string
+	err     error
+}
+
+func (e metadataWriteError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e metadataWriteError) Unwrap() error {
+	return e.err
+}
+
+// writeEditableMetadata sends the editable metadata to the dataset API, guarded by the version ETag, then adds the
+// dataset and version to the collection. The new version ETag is returned, and any error returned is a metadataWriteError
+func writeEditableMetadata(ctx context.Context, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version string, m datasetclient.EditableMetadata, versionEtag, collectionState string) (string, error) {
+	eTag, err := dc.PutMetadata(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, m, versionEtag)
+	if err != nil {
+		return "", metadataWriteError{service: datasetAPIService, message: "error updating metadata", err: err}
+	}
+
+	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, collectionState)
+	if err != nil {
+		return "", metadataWriteError{service: zebedeeService, message: "error adding dataset to collection", err: err}
+	}
+
+	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, collectionState)
+	if err != nil {
+		return "", metadataWriteError{service: zebedeeService, message: "error adding version to collection", err: err}
+	}
+	return eTag, nil
+}
+
+// collectionDatasetState returns the state of the dataset in the collection, so that it is kept when the metadata is saved,
+// or the in progress state if the dataset has not been added to the collection yet
+func collectionDatasetState(ctx context.Context, zc ZebedeeClient, userAccessToken, collectionID, datasetID string) (string, error) {
+	c, err := getCollectionDetails(ctx, zc, userAccessToken, collectionID)
+	if err != nil {
+		return "", err
+	}
+	return datasetStateInCollection(c, datasetID), nil
+}
+
+// datasetStateInCollection returns the state of the dataset in a collection that has already been got, or the in
+// progress state if the dataset has not been added to it yet
+func datasetStateInCollection(c zebedeeclient.Collection, datasetID string) string {
+	for _, d := range c.Datasets {
+		if d.ID == datasetID && d.State != "" {
+			return d.State
+		}
+	}
+	return collectionInProgressState
+}
+
+// writeEditableMetadataError writes the response for an error returned by writeEditableMetadata. A version ETag conflict
+// is reported along with the fields that have been changed since the client loaded the metadata, or with the current
+// metadata if what the client loaded is not known
+func writeEditableMetadataError(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version string, loaded *datasetclient.EditableMetadata, submitted datasetclient.EditableMetadata, versionEtag string, err error, logInfo map[string]interface{}) {
+	ctx := req.Context()
+
+	writeErr := metadataWriteError{service: datasetAPIService, message: "error updating metadata", err: err}
+	errors.As(err, &writeErr)
+
+	if writeErr.service == datasetAPIService && mayBeETagConflict(err) {
+		log.Warn(ctx, "error updating metadata: possible version etag conflict", log.Data(logInfo))
+		writeMetadataConflict(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, loaded, submitted, versionEtag, writeErr.err)
+		return
+	}
+
+	log.Error(ctx, writeErr.message, err, log.Data(logInfo))
+	writeUpstreamErrorResponse(w, req, writeErr.service, writeErr.message, writeErr.err)
+}
@@ -21,6 +21,12 @@ var _ DatasetClient = &DatasetClientMock{}
 //
 //		// make and configure a mocked DatasetClient
 //		mockedDatasetClient := &DatasetClientMock{
+//			CreateDatasetFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, d datasetclient.DatasetDetails) (datasetclient.Dataset, error) {
+//				panic("mock out the CreateDataset method")
+//			},
+//			DeleteDatasetFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) error {
+//				panic("mock out the DeleteDataset method")
+//			},
 //			GetFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.DatasetDetails, error) {
 //				panic("mock out the Get method")
 //			},
@@ -54,10 +60,10 @@ var _ DatasetClient = &DatasetClientMock{}
 //			PutInstanceFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
 //				panic("mock out the PutInstance method")
 //			},
-//			PutMetadataFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) error {
+//			PutMetadataFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
 //				panic("mock out the PutMetadata method")
 //			},
-//			PutVersionFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version) error {
+//			PutVersionFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version, ifMatch string) (string, error) {
 //				panic("mock out the PutVersion method")
 //			},
 //		}
@@ -67,6 +73,12 @@ var _ DatasetClient = &DatasetClientMock{}
 //
 //	}
 type DatasetClientMock struct {
+	// CreateDatasetFunc mocks the CreateDataset method.
+	CreateDatasetFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, d datasetclient.DatasetDetails) (datasetclient.Dataset, error)
+
+	// DeleteDatasetFunc mocks the DeleteDataset method.
+	DeleteDatasetFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) error
+
 	// GetFunc mocks the Get method.
 	GetFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.DatasetDetails, error)
 
@@ -101,13 +113,41 @@ type DatasetClientMock struct {
 	PutInstanceFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error)
 
 	// PutMetadataFunc mocks the PutMetadata method.
-	PutMetadataFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) error
+	PutMetadataFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) (string, error)
 
 	// PutVersionFunc mocks the PutVersion method.
-	PutVersionFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version) error
+	PutVersionFunc func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version, ifMatch string) (string, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// CreateDataset holds details about calls to the CreateDataset method.
+		CreateDataset []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAuthToken is the userAuthToken argument value.
+			UserAuthToken string
+			// ServiceAuthToken is the serviceAuthToken argument value.
+			ServiceAuthToken string
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// D is the d argument value.
+			D datasetclient.DatasetDetails
+		}
+		// DeleteDataset holds details about calls to the DeleteDataset method.
+		DeleteDataset []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAuthToken is the userAuthToken argument value.
+			UserAuthToken string
+			// ServiceAuthToken is the serviceAuthToken argument value.
+			ServiceAuthToken string
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+		}
 		// Get holds details about calls to the Get method.
 		Get []struct {
 			// Ctx is the ctx argument value.
@@ -322,8 +362,12 @@ type DatasetClientMock struct {
 			Version string
 			// V is the v argument value.
 			V datasetclient.Version
+			// IfMatch is the ifMatch argument value.
+			IfMatch string
 		}
 	}
+	lockCreateDataset            sync.RWMutex
+	lockDeleteDataset            sync.RWMutex
 	lockGet                      sync.RWMutex
 	lockGetDatasetCurrentAndNext sync.RWMutex
 	lockGetDatasetsInBatches     sync.RWMutex
@@ -339,6 +383,106 @@ type DatasetClientMock struct {
 	lockPutVersion               sync.RWMutex
 }
 
+// CreateDataset calls CreateDatasetFunc.
+func (mock *DatasetClientMock) CreateDataset(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, d datasetclient.DatasetDetails) (datasetclient.Dataset, error) {
+	if mock.CreateDatasetFunc == nil {
+		panic("DatasetClientMock.CreateDatasetFunc: method is nil but DatasetClient.CreateDataset was just called")
+	}
+	callInfo := struct {
+		Ctx              context.Context
+		UserAuthToken    string
+		ServiceAuthToken string
+		CollectionID     string
+		DatasetID        string
+		D                datasetclient.DatasetDetails
+	}{
+		Ctx:              ctx,
+		UserAuthToken:    userAuthToken,
+		ServiceAuthToken: serviceAuthToken,
+		CollectionID:     collectionID,
+		DatasetID:        datasetID,
+		D:                d,
+	}
+	mock.lockCreateDataset.Lock()
+	mock.calls.CreateDataset = append(mock.calls.CreateDataset, callInfo)
+	mock.lockCreateDataset.Unlock()
+	return mock.CreateDatasetFunc(ctx, userAuthToken, serviceAuthToken, collectionID, datasetID, d)
+}
+
+// CreateDatasetCalls gets all the calls that were made to CreateDataset.
+// Check the length with:
+//
+//	len(mockedDatasetClient.CreateDatasetCalls())
+func (mock *DatasetClientMock) CreateDatasetCalls() []struct {
+	Ctx              context.Context
+	UserAuthToken    string
+	ServiceAuthToken string
+	CollectionID     string
+	DatasetID        string
+	D                datasetclient.DatasetDetails
+} {
+	var calls []struct {
+		Ctx              context.Context
+		UserAuthToken    string
+		ServiceAuthToken string
+		CollectionID     string
+		DatasetID        string
+		D                datasetclient.DatasetDetails
+	}
+	mock.lockCreateDataset.RLock()
+	calls = mock.calls.CreateDataset
+	mock.lockCreateDataset.RUnlock()
+	return calls
+}
+
+// DeleteDataset calls DeleteDatasetFunc.
+func (mock *DatasetClientMock) DeleteDataset(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) error {
+	if mock.DeleteDatasetFunc == nil {
+		panic("DatasetClientMock.DeleteDatasetFunc: method is nil but DatasetClient.DeleteDataset was just called")
+	}
+	callInfo := struct {
+		Ctx              context.Context
+		UserAuthToken    string
+		ServiceAuthToken string
+		CollectionID     string
+		DatasetID        string
+	}{
+		Ctx:              ctx,
+		UserAuthToken:    userAuthToken,
+		ServiceAuthToken: serviceAuthToken,
+		CollectionID:     collectionID,
+		DatasetID:        datasetID,
+	}
+	mock.lockDeleteDataset.Lock()
+	mock.calls.DeleteDataset = append(mock.calls.DeleteDataset, callInfo)
+	mock.lockDeleteDataset.Unlock()
+	return mock.DeleteDatasetFunc(ctx, userAuthToken, serviceAuthToken, collectionID, datasetID)
+}
+
+// DeleteDatasetCalls gets all the calls that were made to DeleteDataset.
+// Check the length with:
+//
+//	len(mockedDatasetClient.DeleteDatasetCalls())
+func (mock *DatasetClientMock) DeleteDatasetCalls() []struct {
+	Ctx              context.Context
+	UserAuthToken    string
+	ServiceAuthToken string
+	CollectionID     string
+	DatasetID        string
+} {
+	var calls []struct {
+		Ctx              context.Context
+		UserAuthToken    string
+		ServiceAuthToken string
+		CollectionID     string
+		DatasetID        string
+	}
+	mock.lockDeleteDataset.RLock()
+	calls = mock.calls.DeleteDataset
+	mock.lockDeleteDataset.RUnlock()
+	return calls
+}
+
 // Get calls GetFunc.
 func (mock *DatasetClientMock) Get(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.DatasetDetails, error) {
 	if mock.GetFunc == nil {
@@ -932,7 +1076,7 @@ func (mock *DatasetClientMock) PutInstanceCalls() []struct {
 }
 
 // PutMetadata calls PutMetadataFunc.
-func (mock *DatasetClientMock) PutMetadata(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) error {
+func (mock *DatasetClientMock) PutMetadata(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, metadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
 	if mock.PutMetadataFunc == nil {
 		panic("DatasetClientMock.PutMetadataFunc: method is nil but DatasetClient.PutMetadata was just called")
 	}
@@ -996,7 +1140,7 @@ func (mock *DatasetClientMock) PutMetadataCalls() []struct {
 }
 
 // PutVersion calls PutVersionFunc.
-func (mock *DatasetClientMock) PutVersion(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version) error {
+func (mock *DatasetClientMock) PutVersion(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string, version string, v datasetclient.Version, ifMatch string) (string, error) {
 	if mock.PutVersionFunc == nil {
 		panic("DatasetClientMock.PutVersionFunc: method is nil but DatasetClient.PutVersion was just called")
 	}
@@ -1009,6 +1153,7 @@ func (mock *DatasetClientMock) PutVersion(ctx context.Context, userAuthToken str
 		Edition          string
 		Version          string
 		V                datasetclient.Version
+		IfMatch          string
 	}{
 		Ctx:              ctx,
 		UserAuthToken:    userAuthToken,
@@ -1018,11 +1163,12 @@ func (mock *DatasetClientMock) PutVersion(ctx context.Context, userAuthToken str
 		Edition:          edition,
 		Version:          version,
 		V:                v,
+		IfMatch:          ifMatch,
 	}
 	mock.lockPutVersion.Lock()
 	mock.calls.PutVersion = append(mock.calls.PutVersion, callInfo)
 	mock.lockPutVersion.Unlock()
-	return mock.PutVersionFunc(ctx, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version, v)
+	return mock.PutVersionFunc(ctx, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version, v, ifMatch)
 }
 
 // PutVersionCalls gets all the calls that were made to PutVersion.
@@ -1038,6 +1184,7 @@ func (mock *DatasetClientMock) PutVersionCalls() []struct {
 	Edition          string
 	Version          string
 	V                datasetclient.Version
+	IfMatch          string
 } {
 	var calls []struct {
 		Ctx              context.Context
@@ -1048,6 +1195,7 @@ func (mock *DatasetClientMock) PutVersionCalls() []struct {
 		Edition          string
 		Version          string
 		V                datasetclient.Version
+		IfMatch          string
 	}
 	mock.lockPutVersion.RLock()
 	calls = mock.calls.PutVersion
@@ -1303,8 +1451,8 @@ var _ BabbageClient = &BabbageClientMock{}
 //
 //		// make and configure a mocked BabbageClient
 //		mockedBabbageClient := &BabbageClientMock{
-//			GetTopicsFunc: func(ctx context.Context, userAccessToken string) (babbageclient.TopicsResult, error) {
-//				panic("mock out the GetTopics method")
+//			GetTopicTreeFunc: func(ctx context.Context, userAccessToken string, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error) {
+//				panic("mock out the GetTopicTree method")
 //			},
 //		}
 //
@@ -1313,54 +1461,60 @@ var _ BabbageClient = &BabbageClientMock{}
 //
 //	}
 type BabbageClientMock struct {
-	// GetTopicsFunc mocks the GetTopics method.
-	GetTopicsFunc func(ctx context.Context, userAccessToken string) (babbageclient.TopicsResult, error)
+	// GetTopicTreeFunc mocks the GetTopicTree method.
+	GetTopicTreeFunc func(ctx context.Context, userAccessToken string, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
-		// GetTopics holds details about calls to the GetTopics method.
-		GetTopics []struct {
+		// GetTopicTree holds details about calls to the GetTopicTree method.
+		GetTopicTree []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// UserAccessToken is the userAccessToken argument value.
 			UserAccessToken string
+			// ServiceAuthToken is the serviceAuthToken argument value.
+			ServiceAuthToken string
 		}
 	}
-	lockGetTopics sync.RWMutex
+	lockGetTopicTree sync.RWMutex
 }
 
-// GetTopics calls GetTopicsFunc.
-func (mock *BabbageClientMock) GetTopics(ctx context.Context, userAccessToken string) (babbageclient.TopicsResult, error) {
-	if mock.GetTopicsFunc == nil {
-		panic("BabbageClientMock.GetTopicsFunc: method is nil but BabbageClient.GetTopics was just called")
+// GetTopicTree calls GetTopicTreeFunc.
+func (mock *BabbageClientMock) GetTopicTree(ctx context.Context, userAccessToken string, serviceAuthToken string) ([]babbageclient.TaxonomyNode, error) {
+	if mock.GetTopicTreeFunc == nil {
+		panic("BabbageClientMock.GetTopicTreeFunc: method is nil but BabbageClient.GetTopicTree was just called")
 	}
 	callInfo := struct {
-		Ctx             context.Context
-		UserAccessToken string
+		Ctx              context.Context
+		UserAccessToken  string
+		ServiceAuthToken string
 	}{
-		Ctx:             ctx,
-		UserAccessToken: userAccessToken,
+		Ctx:              ctx,
+		UserAccessToken:  userAccessToken,
+		ServiceAuthToken: serviceAuthToken,
 	}
-	mock.lockGetTopics.Lock()
-	mock.calls.GetTopics = append(mock.calls.GetTopics, callInfo)
-	mock.lockGetTopics.Unlock()
-	return mock.GetTopicsFunc(ctx, userAccessToken)
+	mock.lockGetTopicTree.Lock()
+	mock.calls.GetTopicTree = append(mock.calls.GetTopicTree, callInfo)
+	mock.lockGetTopicTree.Unlock()
+	return mock.GetTopicTreeFunc(ctx, userAccessToken, serviceAuthToken)
 }
 
-// GetTopicsCalls gets all the calls that were made to GetTopics.
+// GetTopicTreeCalls gets all the calls that were made to GetTopicTree.
 // Check the length with:
 //
-//	len(mockedBabbageClient.GetTopicsCalls())
-func (mock *BabbageClientMock) GetTopicsCalls() []struct {
-	Ctx             context.Context
-	UserAccessToken string
+//	len(mockedBabbageClient.GetTopicTreeCalls())
+func (mock *BabbageClientMock) GetTopicTreeCalls() []struct {
+	Ctx              context.Context
+	UserAccessToken  string
+	ServiceAuthToken string
 } {
 	var calls []struct {
-		Ctx             context.Context
-		UserAccessToken string
+		Ctx              context.Context
+		UserAccessToken  string
+		ServiceAuthToken string
 	}
-	mock.lockGetTopics.RLock()
-	calls = mock.calls.GetTopics
-	mock.lockGetTopics.RUnlock()
+	mock.lockGetTopicTree.RLock()
+	calls = mock.calls.GetTopicTree
+	mock.lockGetTopicTree.RUnlock()
 	return calls
 }
@@ -0,0 +1,163 @@
+package dataset
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// PatchEditableMetadata applies an RFC 7396 JSON merge patch to the current editable metadata of a version, so that only
+// the fields in the patch are changed. It also calls zebedee to update the collection
+func PatchEditableMetadata(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		patchEditableMetadata(w, r, dc, zc, accessToken, serviceAuthToken, collectionID)
+	})
+}
+
+func patchEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	if !isMergePatchContentType(req.Header.Get("Content-Type")) {
+		log.Warn(ctx, "patchEditableMetadata endpoint: unsupported content type", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusUnsupportedMediaType, errCodeUnsupportedMediaType, "content type must be "+mergePatchContentType)
+		return
+	}
+
+	dryRun, err := getBoolQueryParam(req, dryRunQueryParam)
+	if err != nil {
+		log.Error(ctx, "invalid query parameter", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
+	// the patch is written guarded by the version etag the client last read the metadata with, so that changes made
+	// since are reported as a conflict rather than overwritten. A dry run writes nothing so does not need it
+	versionEtag := req.Header.Get("If-Match")
+	if versionEtag == "" && !dryRun {
+		log.Warn(ctx, "patchEditableMetadata endpoint: missing If-Match header", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusPreconditionRequired, errCodePreconditionRequired, "If-Match header is required")
+		return
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "patchEditableMetadata endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err = json.Unmarshal(b, &patch); err != nil || patch == nil {
+		log.Error(ctx, "patchEditableMetadata endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "merge patch must be a JSON object")
+		return
+	}
+
+	if fieldErrors := validateMetadataPatch("", patch); len(fieldErrors) > 0 {
+		writeValidationError(w, req, fieldErrors, logInfo)
+		return
+	}
+
+	v, _, err := dc.GetVersionWithHeaders(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get dataset details", err)
+		return
+	}
+
+	currentDataset := datasetclient.DatasetDetails{}
+	if d.Next != nil {
+		currentDataset = *d.Next
+	}
+	current := mapper.PutMetadata(model.EditMetadata{Dataset: currentDataset, Version: v})
+
+	patched, err := mapper.MergePatchEditableMetadata(current, patch)
+	if err != nil {
+		log.Error(ctx, "patchEditableMetadata endpoint: error applying merge patch", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error applying merge patch")
+		return
+	}
+
+	if dryRun {
+		writeMetadataDryRun(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, patched, logInfo)
+		return
+	}
+
+	collectionState, err := collectionDatasetState(ctx, zc, userAccessToken, collectionID, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get collection details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, zebedeeService, "failed to get collection details", err)
+		return
+	}
+
+	// the patch is applied to the metadata as it is now, not as the client loaded it, so a conflict cannot tell which
+	// fields someone else changed and returns the current metadata instead
+	_, err = writeEditableMetadata(ctx, dc, zc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, patched, versionEtag, collectionState)
+	if err != nil {
+		writeEditableMetadataError(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, nil, patched, versionEtag, err, logInfo)
+		return
+	}
+
+	response, err := json.Marshal(patched)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "patch metadata: request successful", log.Data(logInfo))
+}
+
+// isMergePatchContentType reports whether the content type is a JSON merge patch. Plain JSON is also accepted
+func isMergePatchContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == mergePatchContentType || mediaType == "application/json"
+}
@@ -0,0 +1,209 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPatchEditableMetadata(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata"
+
+	currentDataset := datasetclient.DatasetDetails{
+		ID:          "test-dataset",
+		Title:       "dataset title",
+		Description: "descripton with a typo",
+		License:     "license",
+		Keywords:    &[]string{"one", "two"},
+	}
+	currentVersion := datasetclient.Version{
+		ID:          "version-id",
+		ReleaseDate: "2021-01-01T00:00:00.000Z",
+		Dimensions:  []datasetclient.VersionDimension{{ID: "dim001", Name: "geography", Label: "Geography"}},
+	}
+
+	Convey("test patchEditableMetadata", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			GetVersionWithHeadersFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+				return currentVersion, datasetclient.ResponseHeaders{ETag: "current-etag"}, nil
+			},
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{ID: datasetID, Next: &currentDataset}, nil
+			},
+			PutMetadataFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "", nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID, Datasets: []zebedeeclient.CollectionItem{{ID: "test-dataset", State: "Complete"}}}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+
+		handler := PatchEditableMetadata(mockDatasetClient, mockZebedeeClient, "")
+
+		// the If-Match header defaults to the current version etag, and is left out if set to an empty string
+		doRequest := func(query, body string, headers map[string]string) *httptest.ResponseRecorder {
+			req := newTestRequest("PATCH", "/datasets/test-dataset/editions/test-edition/versions/1/metadata"+query, body, testCollectionID, testUserAuthToken)
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			req.Header.Set("If-Match", "current-etag")
+			for k, v := range headers {
+				if v == "" {
+					req.Header.Del(k)
+					continue
+				}
+				req.Header.Set(k, v)
+			}
+			return doTestRequest(target, req, handler, nil)
+		}
+
+		Convey("on success", func() {
+			rec := doRequest("", `{"description":"description","license":null}`, nil)
+
+			Convey("returns 200 with the patched metadata", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+				var response datasetclient.EditableMetadata
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Description, ShouldEqual, "description")
+				So(response.License, ShouldBeEmpty)
+				So(response.Title, ShouldEqual, "dataset title")
+			})
+
+			Convey("sends the current metadata with only the patched fields changed, guarded by the version etag", func() {
+				So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 1)
+				call := mockDatasetClient.PutMetadataCalls()[0]
+				So(call.VersionEtag, ShouldEqual, "current-etag")
+				So(call.Metadata.Description, ShouldEqual, "description")
+				So(call.Metadata.License, ShouldBeEmpty)
+				So(call.Metadata.Title, ShouldEqual, "dataset title")
+				So(call.Metadata.Keywords, ShouldResemble, []string{"one", "two"})
+				So(call.Metadata.ReleaseDate, ShouldEqual, currentVersion.ReleaseDate)
+				So(call.Metadata.Dimensions, ShouldResemble, currentVersion.Dimensions)
+			})
+
+			Convey("adds the dataset and version to the collection, keeping the dataset's collection state", func() {
+				So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 1)
+				So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "Complete")
+				So(len(mockZebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 1)
+				So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Complete")
+			})
+		})
+
+		Convey("adds a dataset that is not in the collection yet as in progress", func() {
+			mockZebedeeClient.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID}, nil
+			}
+			rec := doRequest("", `{"description":"description"}`, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+		})
+
+		Convey("returns 428 if there is no If-Match header", func() {
+			rec := doRequest("", `{"description":"description"}`, map[string]string{"If-Match": ""})
+
+			So(rec.Code, ShouldEqual, http.StatusPreconditionRequired)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "precondition_required", Message: "If-Match header is required"})
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+
+		Convey("uses the If-Match header as the version etag", func() {
+			rec := doRequest("", `{"description":"description"}`, map[string]string{"If-Match": "client-etag"})
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(mockDatasetClient.PutMetadataCalls()[0].VersionEtag, ShouldEqual, "client-etag")
+		})
+
+		Convey("returns 409 with the current metadata and etag if the version has changed", func() {
+			changedDataset := currentDataset
+			changedDataset.Title = "changed title"
+			mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+				if len(mockDatasetClient.GetDatasetCurrentAndNextCalls()) > 1 {
+					return datasetclient.Dataset{ID: datasetID, Next: &changedDataset}, nil
+				}
+				return datasetclient.Dataset{ID: datasetID, Next: &currentDataset}, nil
+			}
+			mockDatasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "", testStatusError{http.StatusConflict}
+			}
+			rec := doRequest("", `{"description":"description"}`, map[string]string{"If-Match": "stale-etag"})
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			var conflict model.MetadataConflict
+			So(json.Unmarshal(rec.Body.Bytes(), &conflict), ShouldBeNil)
+			So(conflict.CurrentEtag, ShouldEqual, "current-etag")
+			So(conflict.Current, ShouldNotBeNil)
+			So(conflict.Current.Title, ShouldEqual, "changed title")
+			So(conflict.Conflicts, ShouldBeNil)
+			So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("passes through a 409 that is not caused by a stale etag", func() {
+			mockDatasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+				return "", testStatusError{http.StatusConflict}
+			}
+			rec := doRequest("", `{"description":"description"}`, map[string]string{"If-Match": "current-etag"})
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "conflict", Message: "error updating metadata", UpstreamService: "dataset-api", UpstreamStatus: http.StatusConflict})
+		})
+
+		Convey("returns the planned changes on a dry run", func() {
+			rec := doRequest("?dry_run=true", `{"description":"description"}`, map[string]string{"If-Match": ""})
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			var dryRun model.MetadataDryRun
+			So(json.Unmarshal(rec.Body.Bytes(), &dryRun), ShouldBeNil)
+			So(dryRun.Changes, ShouldResemble, []model.FieldChange{
+				{Field: "description", Current: json.RawMessage(`"descripton with a typo"`), Planned: json.RawMessage(`"description"`)},
+			})
+			So(len(mockDatasetClient.PutMetadataCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 415 for a content type other than JSON", func() {
+			rec := doRequest("", `description=description`, map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+
+			So(rec.Code, ShouldEqual, http.StatusUnsupportedMediaType)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "unsupported_media_type", Message: "content type must be application/merge-patch+json"})
+		})
+
+		Convey("returns 400 if the patch is not a JSON object", func() {
+			rec := doRequest("", `["description"]`, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "merge patch must be a JSON object"})
+		})
+
+		Convey("returns 422 for an invalid patch before calling the dataset API", func() {
+			rec := doRequest("", `{"title":" ","dimensions":[]}`, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusUnprocessableEntity)
+			var response model.ValidationError
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(response.Errors, ShouldResemble, []model.FieldError{
+				{Pointer: "/dimensions", Message: "is not an editable metadata field"},
+			})
+			So(len(mockDatasetClient.GetVersionWithHeadersCalls()), ShouldEqual, 0)
+		})
+	})
+}
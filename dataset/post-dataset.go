@@ -0,0 +1,166 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultDatasetType        = "filterable"
+	collectionInProgressState = "InProgress"
+)
+
+var validDatasetTypes = map[string]bool{
+	"filterable":                    true,
+	"nomis":                         true,
+	"cantabular_table":              true,
+	"cantabular_blob":               true,
+	"cantabular_flexible_table":     true,
+	"cantabular_multivariate_table": true,
+	"static":                        true,
+}
+
+// PostDataset creates a new dataset and adds it to the caller's collection
+func PostDataset(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postDataset(w, r, dc, zc, accessToken, serviceAuthToken, collectionID, lang)
+	})
+}
+
+func postDataset(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID, lang string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"collectionID": collectionID,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "postDataset endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var body model.CreateDataset
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "postDataset endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if err = validateCreateDataset(&body); err != nil {
+		log.Error(ctx, "postDataset endpoint: invalid body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	if fieldErrors := validateContacts("/contacts", body.Contacts); len(fieldErrors) > 0 {
+		writeValidationError(w, req, fieldErrors, logInfo)
+		return
+	}
+
+	log.Info(ctx, "calling create dataset", log.Data(logInfo))
+
+	details := datasetclient.DatasetDetails{
+		ID:             datasetID,
+		CollectionID:   collectionID,
+		Title:          body.Title,
+		Description:    body.Description,
+		CanonicalTopic: body.CanonicalTopic,
+		Subtopics:      body.Subtopics,
+		Type:           body.Type,
+	}
+	if len(body.Contacts) > 0 {
+		details.Contacts = &body.Contacts
+	}
+
+	created, err := dc.CreateDataset(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, details)
+	if err != nil {
+		log.Error(ctx, "error creating dataset", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error creating dataset", err)
+		return
+	}
+
+	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, collectionInProgressState)
+	if err != nil {
+		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
+
+		// the dataset is deleted so that it is not left outside of any collection, where it cannot be published
+		if deleteErr := dc.DeleteDataset(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID); deleteErr != nil {
+			log.Error(ctx, "error deleting dataset that could not be added to collection", deleteErr, log.Data(logInfo))
+			errResponse, status := newUpstreamErrorResponse(req, zebedeeService, fmt.Sprintf("dataset %s was created but could not be added to the collection", datasetID), err)
+			errResponse.Code = errCodeNotInCollection
+			writeJSONError(w, req, status, errResponse)
+			return
+		}
+
+		writeUpstreamErrorResponse(w, req, zebedeeService, "error adding dataset to collection", err)
+		return
+	}
+
+	createdDetails := details
+	if created.Next != nil {
+		createdDetails = *created.Next
+	}
+
+	editMetadata := model.EditMetadata{
+		Dataset:         createdDetails,
+		CollectionID:    collectionID,
+		CollectionState: collectionInProgressState,
+	}
+
+	b, err = json.Marshal(editMetadata)
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(b)
+
+	log.Info(ctx, "post dataset: request successful", log.Data(logInfo))
+}
+
+// validateCreateDataset checks the fields required to create a dataset, defaulting the dataset type if it is not set
+func validateCreateDataset(body *model.CreateDataset) error {
+	if strings.TrimSpace(body.Title) == "" {
+		return errors.New("dataset title is required")
+	}
+
+	if body.Type == "" {
+		body.Type = defaultDatasetType
+	}
+	if !validDatasetTypes[body.Type] {
+		return fmt.Errorf("invalid dataset type: %s", body.Type)
+	}
+
+	for _, subtopic := range body.Subtopics {
+		if strings.TrimSpace(subtopic) == "" {
+			return errors.New("subtopics must not be blank")
+		}
+	}
+
+	return nil
+}
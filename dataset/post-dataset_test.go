@@ -0,0 +1,161 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPostDataset(t *testing.T) {
+	t.Parallel()
+
+	const mockDatasetID = "test-dataset"
+
+	createBody := model.CreateDataset{
+		Title:          "Test title",
+		Description:    "Test description",
+		CanonicalTopic: "1234",
+		Subtopics:      []string{"5678"},
+		Contacts:       []datasetclient.Contact{{Name: "contact", Email: "contact@ons.gov.uk", Telephone: "029"}},
+	}
+
+	Convey("test postDataset", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			CreateDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) (datasetclient.Dataset, error) {
+				next := d
+				next.State = "created"
+				return datasetclient.Dataset{ID: datasetID, Next: &next}, nil
+			},
+		}
+
+		mockDatasetClient.DeleteDatasetFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) error {
+			return nil
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+		}
+
+		const target = "/datasets/{datasetID}/create"
+		const url = "/datasets/test-dataset/create"
+		handler := PostDataset(mockDatasetClient, mockZebedeeClient, "")
+
+		Convey("on success", func() {
+			rec := doTestRequest(target, newTestRequest("POST", url, createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			Convey("returns 201 response with the created dataset", func() {
+				So(rec.Code, ShouldEqual, http.StatusCreated)
+
+				var response model.EditMetadata
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Dataset.ID, ShouldEqual, mockDatasetID)
+				So(response.Dataset.Title, ShouldEqual, createBody.Title)
+				So(response.Dataset.Type, ShouldEqual, "filterable")
+				So(response.Dataset.State, ShouldEqual, "created")
+				So(*response.Dataset.Contacts, ShouldResemble, createBody.Contacts)
+				So(response.CollectionID, ShouldEqual, testCollectionID)
+				So(response.CollectionState, ShouldEqual, "InProgress")
+			})
+
+			Convey("calls the dataset API and zebedee with the expected parameters", func() {
+				So(len(mockDatasetClient.CreateDatasetCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.CreateDatasetCalls()[0].DatasetID, ShouldEqual, mockDatasetID)
+				So(mockDatasetClient.CreateDatasetCalls()[0].CollectionID, ShouldEqual, testCollectionID)
+				So(mockDatasetClient.CreateDatasetCalls()[0].D.CanonicalTopic, ShouldEqual, createBody.CanonicalTopic)
+				So(mockDatasetClient.CreateDatasetCalls()[0].D.Subtopics, ShouldResemble, createBody.Subtopics)
+
+				So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 1)
+				So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].DatasetID, ShouldEqual, mockDatasetID)
+				So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+			})
+		})
+
+		Convey("errors if no headers are passed", func() {
+			rec := doTestRequest(target, newTestRequest("POST", url, createBody, "", ""), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
+			So(len(mockDatasetClient.CreateDatasetCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if the title is missing", func() {
+			body := createBody
+			body.Title = " "
+			rec := doTestRequest(target, newTestRequest("POST", url, body, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "dataset title is required"})
+			So(len(mockDatasetClient.CreateDatasetCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if the type is unknown", func() {
+			body := createBody
+			body.Type = "unknown"
+			rec := doTestRequest(target, newTestRequest("POST", url, body, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "invalid dataset type: unknown"})
+			So(len(mockDatasetClient.CreateDatasetCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 422 if a contact has neither name nor email", func() {
+			body := createBody
+			body.Contacts = []datasetclient.Contact{{Telephone: "029"}}
+			rec := doTestRequest(target, newTestRequest("POST", url, body, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+			var response model.ValidationError
+			So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+			So(response.Code, ShouldEqual, "validation_failed")
+			So(response.Errors, ShouldResemble, []model.FieldError{{Pointer: "/contacts/0", Message: "must have a name or an email"}})
+			So(len(mockDatasetClient.CreateDatasetCalls()), ShouldEqual, 0)
+		})
+
+		Convey("handles error from dataset client", func() {
+			mockDatasetClient.CreateDatasetFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{}, errors.New("test dataset API error")
+			}
+			rec := doTestRequest(target, newTestRequest("POST", url, createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("if the dataset cannot be added to the collection", func() {
+			mockZebedeeClient.PutDatasetInCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return errors.New("test zebedee error")
+			}
+
+			Convey("deletes the created dataset and returns the zebedee error", func() {
+				rec := doTestRequest(target, newTestRequest("POST", url, createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error adding dataset to collection", UpstreamService: "zebedee"})
+				So(len(mockDatasetClient.DeleteDatasetCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.DeleteDatasetCalls()[0].DatasetID, ShouldEqual, mockDatasetID)
+				So(mockDatasetClient.DeleteDatasetCalls()[0].CollectionID, ShouldEqual, testCollectionID)
+			})
+
+			Convey("reports that the dataset exists but is not in the collection if it cannot be deleted", func() {
+				mockDatasetClient.DeleteDatasetFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) error {
+					return errors.New("test dataset API error")
+				}
+				rec := doTestRequest(target, newTestRequest("POST", url, createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "not_in_collection", Message: "dataset test-dataset was created but could not be added to the collection", UpstreamService: "zebedee"})
+			})
+		})
+	})
+}
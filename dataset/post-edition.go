@@ -0,0 +1,128 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PostEdition creates a new edition of a dataset by confirming an instance into it, which becomes the edition's first version.
+// Editions are not renamed here, as the dataset API only creates an edition when an instance is confirmed into it and
+// has no call to rename one
+func PostEdition(dc DatasetClient, serviceAuthToken string, batchSize, maxWorkers int) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postEdition(w, r, dc, accessToken, serviceAuthToken, collectionID, batchSize, maxWorkers)
+	})
+}
+
+func postEdition(w http.ResponseWriter, req *http.Request, dc DatasetClient, userAccessToken, serviceAuthToken, collectionID string, batchSize, maxWorkers int) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"collectionID": collectionID,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "postEdition endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var body model.CreateEdition
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "postEdition endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if err = validateCreateEdition(body); err != nil {
+		log.Error(ctx, "postEdition endpoint: invalid body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, err.Error())
+		return
+	}
+	logInfo["edition"] = body.Edition
+	logInfo["instanceID"] = body.InstanceID
+
+	log.Info(ctx, "calling create edition", log.Data(logInfo))
+
+	_, err = dc.GetEdition(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, body.Edition)
+	if err == nil {
+		log.Warn(ctx, "postEdition endpoint: edition already exists", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusConflict, errCodeConflict, fmt.Sprintf("edition %s already exists", body.Edition))
+		return
+	}
+	if upstreamStatusCode(err) != http.StatusNotFound {
+		log.Error(ctx, "error checking for existing edition", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error checking for existing edition", err)
+		return
+	}
+
+	instance, eTag, err := dc.GetInstance(ctx, userAccessToken, serviceAuthToken, collectionID, body.InstanceID, "")
+	if err != nil {
+		log.Error(ctx, "error getting instance", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error getting instance", err)
+		return
+	}
+
+	if instance.Links.Dataset.ID != datasetID {
+		log.Warn(ctx, "postEdition endpoint: instance belongs to another dataset", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, fmt.Sprintf("instance %s does not belong to dataset %s", body.InstanceID, datasetID))
+		return
+	}
+
+	update := datasetclient.UpdateInstance{
+		Edition: body.Edition,
+		State:   editionConfirmedState,
+	}
+	if _, err = dc.PutInstance(ctx, userAccessToken, serviceAuthToken, collectionID, body.InstanceID, update, eTag); err != nil {
+		log.Error(ctx, "error confirming instance edition", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error confirming instance edition", err)
+		return
+	}
+
+	detail, err := getEditionDetail(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, body.Edition, batchSize, maxWorkers)
+	if err != nil {
+		log.Error(ctx, "error getting edition detail from dataset API", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error getting edition detail from dataset API", err)
+		return
+	}
+
+	writeEditionDetail(w, req, http.StatusCreated, detail, logInfo)
+
+	log.Info(ctx, "post edition: request successful", log.Data(logInfo))
+}
+
+// validateCreateEdition checks the edition name can be used in a URL path and that an instance has been given
+func validateCreateEdition(body model.CreateEdition) error {
+	if strings.TrimSpace(body.Edition) == "" {
+		return errors.New("edition is required")
+	}
+	if strings.ContainsAny(body.Edition, "/?#% ") {
+		return fmt.Errorf("invalid edition: %s", body.Edition)
+	}
+	if strings.TrimSpace(body.InstanceID) == "" {
+		return errors.New("instance_id is required")
+	}
+	return nil
+}
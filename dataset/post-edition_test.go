@@ -0,0 +1,145 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPostEdition(t *testing.T) {
+	t.Parallel()
+
+	const batchSize = 10
+	const maxWorkers = 3
+
+	createBody := model.CreateEdition{
+		Edition:    "2021",
+		InstanceID: "instance-001",
+	}
+
+	Convey("test postEdition", t, func() {
+
+		editionExists := false
+		mockDatasetClient := &DatasetClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string) (datasetclient.Dataset, error) {
+				return datasetclient.Dataset{ID: datasetID, Next: &datasetclient.DatasetDetails{Title: "Test title"}}, nil
+			},
+			GetEditionFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string) (datasetclient.Edition, error) {
+				if !editionExists {
+					return datasetclient.Edition{}, testStatusError{http.StatusNotFound}
+				}
+				return datasetclient.Edition{Edition: edition, State: "edition-confirmed", Links: datasetclient.Links{Dataset: datasetclient.Link{ID: datasetID}}}, nil
+			},
+			GetInstanceFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, ifMatch string) (datasetclient.Instance, string, error) {
+				return datasetclient.Instance{Version: datasetclient.Version{ID: instanceID, Links: datasetclient.Links{Dataset: datasetclient.Link{ID: "test-dataset"}}}}, "instance-etag", nil
+			},
+			PutInstanceFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+				editionExists = true
+				return "new-etag", nil
+			},
+			GetVersionsInBatchesFunc: func(ctx context.Context, userAuthToken string, serviceAuthToken string, downloadServiceAuthToken string, collectionID string, datasetID string, edition string, batchSize int, maxWorkers int) (datasetclient.VersionsList, error) {
+				return datasetclient.VersionsList{Items: []datasetclient.Version{{ID: "instance-001", Version: 1, State: "edition-confirmed"}}}, nil
+			},
+		}
+
+		const target = "/datasets/{datasetID}/editions"
+		handler := PostEdition(mockDatasetClient, "", batchSize, maxWorkers)
+
+		Convey("on success", func() {
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			Convey("returns 201 response with the created edition", func() {
+				So(rec.Code, ShouldEqual, http.StatusCreated)
+
+				var response model.EditionDetail
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.ID, ShouldEqual, "2021")
+				So(response.DatasetID, ShouldEqual, "test-dataset")
+				So(response.VersionCount, ShouldEqual, 1)
+				So(response.LatestUnpublishedVersion.ID, ShouldEqual, "instance-001")
+			})
+
+			Convey("confirms the instance into the edition using its etag", func() {
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.PutInstanceCalls()[0].InstanceID, ShouldEqual, "instance-001")
+				So(mockDatasetClient.PutInstanceCalls()[0].I, ShouldResemble, datasetclient.UpdateInstance{Edition: "2021", State: "edition-confirmed"})
+				So(mockDatasetClient.PutInstanceCalls()[0].IfMatch, ShouldEqual, "instance-etag")
+			})
+		})
+
+		Convey("errors if no headers are passed", func() {
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, "", ""), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(len(mockDatasetClient.GetEditionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if the edition is missing", func() {
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", model.CreateEdition{InstanceID: "instance-001"}, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "edition is required"})
+		})
+
+		Convey("errors if the edition cannot be used in a URL path", func() {
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", model.CreateEdition{Edition: "2021/22", InstanceID: "instance-001"}, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "invalid edition: 2021/22"})
+		})
+
+		Convey("errors if the instance ID is missing", func() {
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", model.CreateEdition{Edition: "2021"}, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "instance_id is required"})
+		})
+
+		Convey("returns 409 if the edition already exists", func() {
+			editionExists = true
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "conflict", Message: "edition 2021 already exists"})
+			So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if the instance belongs to another dataset", func() {
+			mockDatasetClient.GetInstanceFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, ifMatch string) (datasetclient.Instance, string, error) {
+				return datasetclient.Instance{Version: datasetclient.Version{Links: datasetclient.Links{Dataset: datasetclient.Link{ID: "other-dataset"}}}}, "instance-etag", nil
+			}
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()).Message, ShouldEqual, "instance instance-001 does not belong to dataset test-dataset")
+			So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("handles error checking for an existing edition", func() {
+			mockDatasetClient.GetEditionFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, datasetID string, edition string) (datasetclient.Edition, error) {
+				return datasetclient.Edition{}, errors.New("test dataset API error")
+			}
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(len(mockDatasetClient.GetInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("passes through a stale instance etag", func() {
+			mockDatasetClient.PutInstanceFunc = func(ctx context.Context, userAuthToken string, serviceAuthToken string, collectionID string, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+				return "", testStatusError{http.StatusConflict}
+			}
+			rec := doTestRequest(target, newTestRequest("POST", "/datasets/test-dataset/editions", createBody, testCollectionID, testUserAuthToken), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			So(decodeErrorResponse(rec.Body.String()).Message, ShouldEqual, "error confirming instance edition")
+		})
+	})
+}
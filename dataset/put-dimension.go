@@ -0,0 +1,151 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	dphandlers "github.com/ONSdigital/dp-net/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PutDimension updates the label and description of a single dimension of a version's instance
+func PutDimension(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putDimension(w, r, dc, zc, accessToken, serviceAuthToken, collectionID)
+	})
+}
+
+func putDimension(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		writeHeadersErrorResponse(w, req, err)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	dimensionName := vars["dimensionName"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+		"dimension": dimensionName,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "putDimension endpoint: error reading body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
+		return
+	}
+
+	var body model.EditDimension
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "putDimension endpoint: error unmarshalling body", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if strings.TrimSpace(body.Label) == "" {
+		log.Warn(ctx, "putDimension endpoint: missing dimension label", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "dimension label is required")
+		return
+	}
+
+	// the etag the client read the dimensions with, so that changes made since are not overwritten
+	instanceEtag := req.Header.Get("If-Match")
+	if instanceEtag == "" {
+		instanceEtag = body.InstanceEtag
+	}
+	if instanceEtag == "" {
+		log.Warn(ctx, "putDimension endpoint: missing instance etag", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusPreconditionRequired, errCodePreconditionRequired, "instance_etag is required")
+		return
+	}
+
+	v, err := dc.GetVersion(ctx, userAccessToken, serviceAuthToken, "", collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get version details", err)
+		return
+	}
+	logInfo["instanceID"] = v.ID
+
+	instance, eTag, err := dc.GetInstance(ctx, userAccessToken, serviceAuthToken, collectionID, v.ID, "")
+	if err != nil {
+		log.Error(ctx, "failed Get instance details", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "failed to get instance details", err)
+		return
+	}
+
+	// the dataset API replaces the whole dimension list, so the unchanged dimensions are sent back as they are
+	dimensions := append([]datasetclient.VersionDimension{}, instance.Dimensions...)
+	index := -1
+	for i := range dimensions {
+		if dimensions[i].Name == dimensionName {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		log.Warn(ctx, "putDimension endpoint: dimension not found", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("dimension %s not found", dimensionName))
+		return
+	}
+	dimensions[index].Label = body.Label
+	dimensions[index].Description = body.Description
+
+	if eTag != instanceEtag {
+		writeInstanceConflict(w, req, v.ID, eTag, dimensions, instance.Dimensions)
+		return
+	}
+
+	update := datasetclient.UpdateInstance{
+		InstanceID: v.ID,
+		Dimensions: dimensions,
+	}
+	newETag, err := dc.PutInstance(ctx, userAccessToken, serviceAuthToken, collectionID, v.ID, update, instanceEtag)
+	if err != nil {
+		log.Error(ctx, "error updating dimension", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error updating dimension", err)
+		return
+	}
+
+	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, collectionInProgressState)
+	if err != nil {
+		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, zebedeeService, "error adding version to collection", err)
+		return
+	}
+
+	response, err := json.Marshal(dimensions[index])
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusInternalServerError, errCodeInternalError, "error marshalling response to json")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if newETag != "" {
+		w.Header().Set("ETag", newETag)
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "put dimension: request successful", log.Data(logInfo))
+}
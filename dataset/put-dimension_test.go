@@ -0,0 +1,168 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPutDimension(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimensionName}"
+
+	editBody := model.EditDimension{Label: "new label", Description: "new description", InstanceEtag: "instance-etag"}
+
+	instanceDimensions := []datasetclient.VersionDimension{
+		{ID: "dim001", Name: "geography", Label: "original geography", Description: "original"},
+		{ID: "dim002", Name: "time", Label: "original time"},
+	}
+
+	Convey("test putDimension", t, func() {
+
+		mockDatasetClient := &DatasetClientMock{
+			GetVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, error) {
+				return datasetclient.Version{ID: "instance-001"}, nil
+			},
+			GetInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID, ifMatch string) (datasetclient.Instance, string, error) {
+				return datasetclient.Instance{Version: datasetclient.Version{ID: instanceID, Dimensions: instanceDimensions}}, "instance-etag", nil
+			},
+			PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+				return "new-etag", nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+
+		handler := PutDimension(mockDatasetClient, mockZebedeeClient, "")
+
+		doRequest := func(dimension string, body interface{}) *httptest.ResponseRecorder {
+			return doTestRequest(target, newTestRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1/dimensions/"+dimension, body, testCollectionID, testUserAuthToken), handler, nil)
+		}
+
+		Convey("on success", func() {
+			rec := doRequest("geography", editBody)
+
+			Convey("returns 200 response with the updated dimension and new etag", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(rec.Header().Get("ETag"), ShouldEqual, "new-etag")
+
+				var response datasetclient.VersionDimension
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response, ShouldResemble, datasetclient.VersionDimension{ID: "dim001", Name: "geography", Label: "new label", Description: "new description"})
+			})
+
+			Convey("updates only the named dimension using the instance etag", func() {
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 1)
+				call := mockDatasetClient.PutInstanceCalls()[0]
+				So(call.InstanceID, ShouldEqual, "instance-001")
+				So(call.IfMatch, ShouldEqual, "instance-etag")
+				So(call.I.Dimensions, ShouldResemble, []datasetclient.VersionDimension{
+					{ID: "dim001", Name: "geography", Label: "new label", Description: "new description"},
+					{ID: "dim002", Name: "time", Label: "original time"},
+				})
+				So(instanceDimensions[0].Label, ShouldEqual, "original geography")
+			})
+
+			Convey("adds the version to the collection", func() {
+				So(len(mockZebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 1)
+				So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+			})
+		})
+
+		Convey("errors if no headers are passed", func() {
+			rec := doTestRequest(target, newTestRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1/dimensions/geography", editBody, "", ""), handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("errors if the label is missing", func() {
+			rec := doRequest("geography", model.EditDimension{Description: "description", InstanceEtag: "instance-etag"})
+
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_request_body", Message: "dimension label is required"})
+		})
+
+		Convey("accepts the instance etag in an If-Match header", func() {
+			req := newTestRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1/dimensions/geography", model.EditDimension{Label: "new label"}, testCollectionID, testUserAuthToken)
+			req.Header.Set("If-Match", "instance-etag")
+			rec := doTestRequest(target, req, handler, nil)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(mockDatasetClient.PutInstanceCalls()[0].IfMatch, ShouldEqual, "instance-etag")
+		})
+
+		Convey("errors if the instance etag is missing", func() {
+			rec := doRequest("geography", model.EditDimension{Label: "new label"})
+
+			So(rec.Code, ShouldEqual, http.StatusPreconditionRequired)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "precondition_required", Message: "instance_etag is required"})
+			So(len(mockDatasetClient.GetVersionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 409 if the instance has changed since the client read it", func() {
+			rec := doRequest("geography", model.EditDimension{Label: "new label", InstanceEtag: "stale-etag"})
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			var conflict model.MetadataConflict
+			So(json.Unmarshal(rec.Body.Bytes(), &conflict), ShouldBeNil)
+			So(conflict.Code, ShouldEqual, "conflict")
+			So(conflict.CurrentEtag, ShouldEqual, "instance-etag")
+			So(conflict.Conflicts, ShouldHaveLength, 1)
+			So(conflict.Conflicts[0].Field, ShouldEqual, "dimensions")
+			So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("returns 404 if the instance has no dimension with that name", func() {
+			rec := doRequest("sex", editBody)
+
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+			So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "not_found", Message: "dimension sex not found"})
+			So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("passes through a stale instance etag", func() {
+			mockDatasetClient.PutInstanceFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+				return "", testStatusError{http.StatusConflict}
+			}
+			rec := doRequest("geography", editBody)
+
+			So(rec.Code, ShouldEqual, http.StatusConflict)
+			So(decodeErrorResponse(rec.Body.String()).Message, ShouldEqual, "error updating dimension")
+			So(len(mockZebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+		})
+
+		Convey("handles error getting the instance", func() {
+			mockDatasetClient.GetInstanceFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID, ifMatch string) (datasetclient.Instance, string, error) {
+				return datasetclient.Instance{}, "", errors.New("test dataset API error")
+			}
+			rec := doRequest("geography", editBody)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+		})
+
+		Convey("handles error from zebedee client", func() {
+			mockZebedeeClient.PutDatasetVersionInCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return errors.New("test zebedee error")
+			}
+			rec := doRequest("geography", editBody)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(decodeErrorResponse(rec.Body.String()).UpstreamService, ShouldEqual, "zebedee")
+		})
+	})
+}
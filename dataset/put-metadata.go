@@ -15,19 +15,19 @@ import (
 )
 
 // PutMetadata updates all the dataset, version and dimension object fields
-func PutMetadata(dc DatasetClient, zc ZebedeeClient) http.HandlerFunc {
+func PutMetadata(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		putMetadata(w, r, dc, zc, accessToken, collectionID, lang)
+		putMetadata(w, r, dc, zc, accessToken, serviceAuthToken, collectionID, lang)
 	})
 }
 
-func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, collectionID, lang string) {
+func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID, lang string) {
 	ctx := req.Context()
 
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
@@ -42,56 +42,118 @@ func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc
 		"version":   version,
 	}
 
+	dryRun, err := getBoolQueryParam(req, dryRunQueryParam)
+	if err != nil {
+		log.Error(ctx, "invalid query parameter", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
 	b, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		log.Error(ctx, "putMetadata endpoint: error reading body", err, log.Data(logInfo))
-		http.Error(w, "error reading body", http.StatusBadRequest)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
 		return
 	}
 
 	var body model.EditMetadata
 	if err = json.Unmarshal(b, &body); err != nil {
 		log.Error(ctx, "putMetadata endpoint: error unmarshalling body", err, log.Data(logInfo))
-		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
+		return
+	}
+
+	if fieldErrors := validateEditMetadata(body); len(fieldErrors) > 0 {
+		writeValidationError(w, req, fieldErrors, logInfo)
+		return
+	}
+
+	if dryRun {
+		// the dimensions are written to the instance from the top level of the request, so these are the ones that would change
+		planned := body
+		if body.Dimensions != nil {
+			planned.Version.Dimensions = body.Dimensions
+		}
+		writeMetadataDryRun(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, mapper.PutMetadata(planned), logInfo)
 		return
 	}
 
-	err = dc.PutDataset(ctx, userAccessToken, "", collectionID, datasetID, body.Dataset)
+	// the version and its instance share an ETag, which older editors send as the instance etag
+	eTag := body.VersionEtag
+	if eTag == "" {
+		eTag = body.InstanceEtag
+	}
+	if eTag == "" {
+		log.Warn(ctx, "putMetadata endpoint: missing version etag", log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusPreconditionRequired, errCodePreconditionRequired, "version_etag is required")
+		return
+	}
+
+	// snapshot the current state so that the dataset API updates can be reverted if a later step fails
+	snapshot, err := getMetadataSnapshot(ctx, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, body.Version.ID)
+	if err == errNoNextDataset {
+		log.Error(ctx, "putMetadata endpoint: dataset has no unpublished state to update", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusNotFound, errCodeNotFound, "dataset has no unpublished state to update")
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error getting current metadata", err, log.Data(logInfo))
+		writeUpstreamErrorResponse(w, req, datasetAPIService, "error getting current metadata", err)
+		return
+	}
+
+	// reject the update before anything is written if the version or dimensions were changed after the editor loaded them
+	if eTag != snapshot.eTag {
+		writeInstanceConflict(w, req, body.Version.ID, snapshot.eTag, body.Dimensions, snapshot.instance.Dimensions)
+		return
+	}
+
+	update := &metadataUpdate{dataset: body.Dataset, version: body.Version, eTag: eTag}
+
+	err = dc.PutDataset(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, body.Dataset)
 	if err != nil {
 		log.Error(ctx, "error updating dataset", err, log.Data(logInfo))
-		http.Error(w, "error updating dataset", http.StatusInternalServerError)
+		update.fail(w, req, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, stepUpdateDataset, err)
 		return
 	}
+	update.succeeded(stepUpdateDataset)
 
-	err = dc.PutVersion(ctx, userAccessToken, "", collectionID, datasetID, edition, version, body.Version)
+	// each write to the version or its instance changes their shared ETag, so the next write is guarded by the new one
+	eTag, err = dc.PutVersion(ctx, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, body.Version, update.eTag)
 	if err != nil {
 		log.Error(ctx, "error updating version", err, log.Data(logInfo))
-		http.Error(w, "error updating version", http.StatusInternalServerError)
+		update.fail(w, req, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, stepUpdateVersion, err)
 		return
 	}
+	update.eTag = eTag
+	update.succeeded(stepUpdateVersion)
 
 	instance := datasetclient.UpdateInstance{}
 	instance.InstanceID = body.Version.ID
 	instance.Dimensions = body.Dimensions
 
-	_, err = dc.PutInstance(ctx, userAccessToken, "", collectionID, body.Version.ID, instance, "")
+	eTag, err = dc.PutInstance(ctx, userAccessToken, serviceAuthToken, collectionID, body.Version.ID, instance, update.eTag)
 	if err != nil {
 		log.Error(ctx, "error updating dimensions", err, log.Data(logInfo))
-		http.Error(w, "error updating dimensions", http.StatusInternalServerError)
+		update.fail(w, req, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, stepUpdateDimensions, err)
 		return
 	}
+	update.eTag = eTag
+	update.succeeded(stepUpdateDimensions)
 
 	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, body.CollectionState)
 	if err != nil {
 		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
-		http.Error(w, "error adding dataset to collection", http.StatusInternalServerError)
+		update.fail(w, req, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, stepAddDatasetToCollection, err)
 		return
 	}
+	update.succeeded(stepAddDatasetToCollection)
 
 	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, body.CollectionState)
 	if err != nil {
 		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
-		http.Error(w, "error adding version to collection", http.StatusInternalServerError)
+		update.fail(w, req, dc, snapshot, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, stepAddVersionToCollection, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -103,19 +165,19 @@ func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc
 // PutEditableMetadata updates a given list of metadata fields, agreed as being editable for both a dataset and a version object
 // This new endpoint makes a unique call to the dataset api updating only the relevant metadata fields in a transactional way
 // It also calls zebedee to update the collection
-func PutEditableMetadata(dc DatasetClient, zc ZebedeeClient) http.HandlerFunc {
+func PutEditableMetadata(dc DatasetClient, zc ZebedeeClient, serviceAuthToken string) http.HandlerFunc {
 	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		putEditableMetadata(w, r, dc, zc, accessToken, collectionID)
+		putEditableMetadata(w, r, dc, zc, accessToken, serviceAuthToken, collectionID)
 	})
 }
 
-func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, collectionID string) {
+func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetClient, zc ZebedeeClient, userAccessToken, serviceAuthToken, collectionID string) {
 	ctx := req.Context()
 
 	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeHeadersErrorResponse(w, req, err)
 		return
 	}
 
@@ -130,49 +192,57 @@ func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetCli
 		"version":   version,
 	}
 
+	dryRun, err := getBoolQueryParam(req, dryRunQueryParam)
+	if err != nil {
+		log.Error(ctx, "invalid query parameter", err, log.Data(logInfo))
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidQueryParameter, err.Error())
+		return
+	}
+
 	b, err := io.ReadAll(req.Body)
 	if err != nil {
 		log.Error(ctx, "putMetadata endpoint: error reading body", err, log.Data(logInfo))
-		http.Error(w, "error reading body", http.StatusBadRequest)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error reading body")
 		return
 	}
 
 	var body model.EditMetadata
 	if err = json.Unmarshal(b, &body); err != nil {
 		log.Error(ctx, "putMetadata endpoint: error unmarshalling body", err, log.Data(logInfo))
-		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		writeErrorResponse(w, req, http.StatusBadRequest, errCodeInvalidRequestBody, "error unmarshalling body")
 		return
 	}
 
-	versionEtag := body.VersionEtag
-
-	editableMetadata := mapper.PutMetadata(body)
-
-	err = dc.PutMetadata(ctx, userAccessToken, "", collectionID, datasetID, edition, version, editableMetadata, versionEtag)
-	if err != nil {
-		log.Error(ctx, "error updating metadata", err, log.Data(logInfo))
-		http.Error(w, "error updating metadata", http.StatusInternalServerError)
+	if fieldErrors := validateEditMetadata(body); len(fieldErrors) > 0 {
+		writeValidationError(w, req, fieldErrors, logInfo)
 		return
 	}
 
-	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, body.CollectionState)
-	if err != nil {
-		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
-		http.Error(w, "error adding dataset to collection", http.StatusInternalServerError)
+	if dryRun {
+		writeMetadataDryRun(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, mapper.PutMetadata(body), logInfo)
 		return
 	}
 
-	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, body.CollectionState)
+	versionEtag := body.VersionEtag
+
+	editableMetadata := mapper.PutMetadata(body)
+
+	// the metadata as the client loaded it is used to tell which fields someone else has changed if the version etag is stale.
+	// Without it every submitted field that differs from the current metadata is reported
+	loaded := editableMetadata
+	if body.Original != nil {
+		loaded = mapper.PutMetadata(*body.Original)
+	}
+
+	_, err = writeEditableMetadata(ctx, dc, zc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, editableMetadata, versionEtag, body.CollectionState)
 	if err != nil {
-		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
-		http.Error(w, "error adding version to collection", http.StatusInternalServerError)
+		writeEditableMetadataError(w, req, dc, userAccessToken, serviceAuthToken, collectionID, datasetID, edition, version, &loaded, editableMetadata, versionEtag, err, logInfo)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 	if _, err = w.Write(b); err != nil {
 		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
-		http.Error(w, "failed to write response body", http.StatusInternalServerError)
 		return
 	}
 
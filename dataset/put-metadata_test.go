@@ -19,9 +19,25 @@ import (
 )
 
 var (
-	metadataBody = `{"dataset":{"id":"test-dataset"},"version":{"id":"1"},"instance":{},"collection_id":"testcollection","collection_state":"InProgress"}`
+	metadataBody = `{"dataset":{"id":"test-dataset","title":"test title"},"version":{"id":"1"},"instance":{},"collection_id":"testcollection","collection_state":"InProgress","instance_etag":"instance-etag"}`
+
+	snapshotDataset  = datasetclient.DatasetDetails{ID: "test-dataset", Title: "original title"}
+	snapshotVersion  = datasetclient.Version{ID: "1", ReleaseDate: "2020-11-07T00:00:00.000Z"}
+	snapshotInstance = datasetclient.Instance{Version: datasetclient.Version{ID: "1", Dimensions: []datasetclient.VersionDimension{{ID: "dim001", Label: "original label"}}}}
 )
 
+func getDatasetCurrentAndNextSnapshot(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+	return datasetclient.Dataset{ID: datasetID, Next: &snapshotDataset}, nil
+}
+
+func getVersionSnapshot(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, error) {
+	return snapshotVersion, nil
+}
+
+func getInstanceSnapshot(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID, ifMatch string) (datasetclient.Instance, string, error) {
+	return snapshotInstance, "instance-etag", nil
+}
+
 func TestUnitPutMetadata(t *testing.T) {
 
 	b := metadataBody
@@ -30,11 +46,14 @@ func TestUnitPutMetadata(t *testing.T) {
 		Convey("on success", func() {
 
 			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc:               getVersionSnapshot,
+				GetInstanceFunc:              getInstanceSnapshot,
 				PutDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error {
 					return nil
 				},
-				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version) error {
-					return nil
+				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					return "", nil
 				},
 				PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
 					return "", nil
@@ -55,7 +74,7 @@ func TestUnitPutMetadata(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
 
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
@@ -66,11 +85,14 @@ func TestUnitPutMetadata(t *testing.T) {
 		Convey("errors if no headers are passed", func() {
 
 			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc:               getVersionSnapshot,
+				GetInstanceFunc:              getInstanceSnapshot,
 				PutDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error {
 					return nil
 				},
-				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version) error {
-					return nil
+				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					return "", nil
 				},
 				PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
 					return "", nil
@@ -91,7 +113,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -101,7 +123,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no collection ID header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 				})
 			})
 
@@ -110,7 +132,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -120,7 +142,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				Convey("returns error body", func() {
 					router.ServeHTTP(rec, req)
 					response := rec.Body.String()
-					So(response, ShouldResemble, "no user access token header set\n")
+					So(decodeErrorResponse(response), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 				})
 			})
 		})
@@ -128,11 +150,14 @@ func TestUnitPutMetadata(t *testing.T) {
 		Convey("handles error from dataset client", func() {
 
 			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc:               getVersionSnapshot,
+				GetInstanceFunc:              getInstanceSnapshot,
 				PutDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error {
 					return errors.New("test dataset API error")
 				},
-				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version) error {
-					return nil
+				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					return "", nil
 				},
 				PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
 					return "", nil
@@ -153,15 +178,314 @@ func TestUnitPutMetadata(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser")
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
 
 			Convey("returns 500 response and error body", func() {
 				router.ServeHTTP(rec, req)
 				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
-				response := rec.Body.String()
-				So(response, ShouldResemble, "error updating dataset\n")
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Failed, ShouldEqual, "update dataset")
+				So(response.Succeeded, ShouldBeEmpty)
+				So(response.RolledBack, ShouldBeEmpty)
+				So(len(mockDatasetClient.PutVersionCalls()), ShouldEqual, 0)
+			})
+
+		})
+
+		Convey("handles error getting the current state", func() {
+
+			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, error) {
+					return datasetclient.Version{}, &testCliError{}
+				},
+			}
+
+			req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(b))
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, &ZebedeeClientMock{}, ""))
+
+			Convey("returns the upstream status without updating anything", func() {
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusNotFound)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "not_found", Message: "error getting current metadata", UpstreamService: "dataset-api", UpstreamStatus: http.StatusNotFound})
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 0)
+			})
+
+			Convey("returns 404 without updating anything if the dataset has no unpublished state", func() {
+				mockDatasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+					return datasetclient.Dataset{ID: datasetID}, nil
+				}
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusNotFound)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "not_found", Message: "dataset has no unpublished state to update"})
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 0)
+			})
+		})
+
+		Convey("rolls back completed steps when a later step fails", func() {
+
+			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc:               getVersionSnapshot,
+				GetInstanceFunc:              getInstanceSnapshot,
+				PutDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error {
+					return nil
+				},
+				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					return "updated-version-etag", nil
+				},
+				PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+					return "updated-instance-etag", nil
+				},
+			}
+
+			mockZebedeeClient := &ZebedeeClientMock{
+				PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+					return nil
+				},
+				PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+					return errors.New("test zebedee error")
+				},
+			}
+
+			req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(b))
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
+
+			Convey("restores the snapshot and reports each step", func() {
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Failed, ShouldEqual, "add version to collection")
+				So(response.Succeeded, ShouldResemble, []string{"update dataset", "update version", "update dimensions", "add dataset to collection"})
+				So(response.RolledBack, ShouldResemble, []string{"update dimensions", "update version", "update dataset"})
+				So(response.RollbackFailed, ShouldBeEmpty)
+
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 2)
+				So(mockDatasetClient.PutDatasetCalls()[1].D, ShouldResemble, snapshotDataset)
+				So(len(mockDatasetClient.PutVersionCalls()), ShouldEqual, 2)
+				So(mockDatasetClient.PutVersionCalls()[1].V, ShouldResemble, snapshotVersion)
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 2)
+				So(mockDatasetClient.PutInstanceCalls()[1].I.Dimensions, ShouldResemble, snapshotInstance.Dimensions)
+				So(mockDatasetClient.PutInstanceCalls()[0].IfMatch, ShouldEqual, "updated-version-etag")
+				So(mockDatasetClient.PutInstanceCalls()[1].IfMatch, ShouldEqual, "updated-instance-etag")
+				So(mockDatasetClient.PutVersionCalls()[1].IfMatch, ShouldEqual, "updated-instance-etag")
 			})
 
+			Convey("reports the dataset as partly rolled back if fields it set cannot be cleared", func() {
+				body := `{"dataset":{"id":"test-dataset","title":"test title","description":"new description","keywords":["one"]},"version":{"id":"1"},"instance":{},"collection_id":"testcollection","collection_state":"InProgress","instance_etag":"instance-etag"}`
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(body))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.RolledBack, ShouldResemble, []string{"update dimensions", "update version"})
+				So(response.PartlyRolledBack, ShouldResemble, []string{"update dataset"})
+				So(response.NotRestored, ShouldResemble, []string{"description"})
+
+				restored := snapshotDataset
+				restored.Keywords = &[]string{}
+				So(mockDatasetClient.PutDatasetCalls()[1].D, ShouldResemble, restored)
+			})
+
+			Convey("clears the version lists the update set and reports the version fields that cannot be cleared", func() {
+				body := `{"dataset":{"id":"test-dataset","title":"test title"},"version":{"id":"1","usage_notes":[{"title":"note"}],"alerts":[{"description":"alert"}],"latest_changes":[{"name":"change"}],"lowest_geography":"ltla"},"instance":{},"collection_id":"testcollection","collection_state":"InProgress","instance_etag":"instance-etag"}`
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(body))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.RolledBack, ShouldResemble, []string{"update dimensions", "update dataset"})
+				So(response.PartlyRolledBack, ShouldResemble, []string{"update version"})
+				So(response.NotRestored, ShouldResemble, []string{"lowest_geography"})
+
+				restored := snapshotVersion
+				restored.UsageNotes = &[]datasetclient.UsageNote{}
+				restored.Alerts = &[]datasetclient.Alert{}
+				restored.LatestChanges = []datasetclient.Change{}
+				So(mockDatasetClient.PutVersionCalls()[1].V, ShouldResemble, restored)
+			})
+
+			Convey("reports steps that could not be rolled back", func() {
+				mockDatasetClient.PutVersionFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					if v.ReleaseDate == snapshotVersion.ReleaseDate {
+						return "", errors.New("test dataset API error")
+					}
+					return "updated-version-etag", nil
+				}
+				router.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.RolledBack, ShouldResemble, []string{"update dimensions", "update dataset"})
+				So(response.RollbackFailed, ShouldResemble, []string{"update version"})
+			})
+		})
+
+		Convey("uses the etag shared by the version and its instance to guard the updates", func() {
+
+			// like the dataset API, each write to the version or its instance changes their shared etag and a write
+			// guarded by any other etag is rejected
+			currentETag := "instance-etag"
+			writeVersionOrInstance := func(ifMatch, newETag string) (string, error) {
+				if ifMatch != currentETag {
+					return "", testStatusError{http.StatusConflict}
+				}
+				currentETag = newETag
+				return newETag, nil
+			}
+
+			mockDatasetClient := &DatasetClientMock{
+				GetDatasetCurrentAndNextFunc: getDatasetCurrentAndNextSnapshot,
+				GetVersionFunc:               getVersionSnapshot,
+				GetInstanceFunc:              getInstanceSnapshot,
+				PutDatasetFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string, d datasetclient.DatasetDetails) error {
+					return nil
+				},
+				PutVersionFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					return writeVersionOrInstance(ifMatch, "version-updated-etag")
+				},
+				PutInstanceFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, instanceID string, i datasetclient.UpdateInstance, ifMatch string) (string, error) {
+					return writeVersionOrInstance(ifMatch, "instance-updated-etag")
+				},
+			}
+
+			mockZebedeeClient := &ZebedeeClientMock{
+				PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+					return nil
+				},
+				PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+					return nil
+				},
+			}
+
+			router := mux.NewRouter()
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, ""))
+
+			doRequest := func(body string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(body))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec
+			}
+
+			Convey("guards the dimension update with the etag returned by the version update", func() {
+				rec := doRequest(b)
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(len(mockDatasetClient.PutVersionCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.PutVersionCalls()[0].IfMatch, ShouldEqual, "instance-etag")
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 1)
+				So(mockDatasetClient.PutInstanceCalls()[0].IfMatch, ShouldEqual, "version-updated-etag")
+			})
+
+			Convey("accepts the etag as the version etag", func() {
+				rec := doRequest(`{"dataset":{"id":"test-dataset","title":"test title"},"version":{"id":"1"},"collection_state":"InProgress","version_etag":"instance-etag"}`)
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(mockDatasetClient.PutVersionCalls()[0].IfMatch, ShouldEqual, "instance-etag")
+			})
+
+			Convey("returns 428 if the etag is missing", func() {
+				rec := doRequest(`{"dataset":{"id":"test-dataset","title":"test title"},"version":{"id":"1"},"collection_state":"InProgress"}`)
+				So(rec.Code, ShouldEqual, http.StatusPreconditionRequired)
+				So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "precondition_required", Message: "version_etag is required"})
+				So(len(mockDatasetClient.GetInstanceCalls()), ShouldEqual, 0)
+			})
+
+			Convey("returns 422 with every invalid field before calling the dataset API", func() {
+				rec := doRequest(`{"dataset":{"id":"test-dataset","title":""},"version":{"id":"1","release_date":"tomorrow"},"dimensions":[{"id":"dim001"}],"instance_etag":"instance-etag"}`)
+				So(rec.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+				var response model.ValidationError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Code, ShouldEqual, "validation_failed")
+				So(response.Errors, ShouldHaveLength, 3)
+				So(response.Errors[0].Pointer, ShouldEqual, "/dataset/title")
+				So(response.Errors[1].Pointer, ShouldEqual, "/version/release_date")
+				So(response.Errors[2].Pointer, ShouldEqual, "/dimensions/0/label")
+
+				So(len(mockDatasetClient.GetDatasetCurrentAndNextCalls()), ShouldEqual, 0)
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 0)
+			})
+
+			Convey("returns the planned changes, including the top level dimensions, on a dry run", func() {
+				mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+					return snapshotVersion, datasetclient.ResponseHeaders{ETag: "version-etag"}, nil
+				}
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1?dry_run=true", bytes.NewBufferString(`{"dataset":{"id":"test-dataset","title":"original title"},"version":{"id":"1","release_date":"2020-11-07T00:00:00.000Z"},"dimensions":[{"id":"dim001","label":"new label"}],"instance_etag":"instance-etag"}`))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var dryRun model.MetadataDryRun
+				So(json.Unmarshal(rec.Body.Bytes(), &dryRun), ShouldBeNil)
+				So(dryRun.VersionEtag, ShouldEqual, "version-etag")
+				So(dryRun.Changes, ShouldHaveLength, 1)
+				So(dryRun.Changes[0].Field, ShouldEqual, "dimensions")
+
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 0)
+				So(len(mockDatasetClient.PutVersionCalls()), ShouldEqual, 0)
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+				So(len(mockZebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+			})
+
+			Convey("returns 409 with the current etag and dimensions if the instance etag is stale", func() {
+				rec := doRequest(`{"dataset":{"id":"test-dataset","title":"test title"},"version":{"id":"1"},"dimensions":[{"id":"dim001","label":"new label"}],"collection_state":"InProgress","instance_etag":"stale-etag"}`)
+				So(rec.Code, ShouldEqual, http.StatusConflict)
+
+				var response model.MetadataConflict
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Code, ShouldEqual, "conflict")
+				So(response.CurrentEtag, ShouldEqual, "instance-etag")
+				So(len(response.Conflicts), ShouldEqual, 1)
+				So(response.Conflicts[0].Field, ShouldEqual, "dimensions")
+				So(string(response.Conflicts[0].Submitted), ShouldEqual, `[{"id":"dim001","name":"","label":"new label","description":""}]`)
+				So(string(response.Conflicts[0].Current), ShouldEqual, `[{"id":"dim001","name":"","label":"original label","description":""}]`)
+
+				So(len(mockDatasetClient.PutDatasetCalls()), ShouldEqual, 0)
+				So(len(mockDatasetClient.PutVersionCalls()), ShouldEqual, 0)
+				So(len(mockDatasetClient.PutInstanceCalls()), ShouldEqual, 0)
+			})
+
+			Convey("rolls back the dataset and version if the instance changes during the update", func() {
+				mockDatasetClient.PutVersionFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, v datasetclient.Version, ifMatch string) (string, error) {
+					eTag, err := writeVersionOrInstance(ifMatch, "version-updated-etag")
+					// another user changes the instance straight after the version is updated
+					currentETag = "changed-by-another-user-etag"
+					return eTag, err
+				}
+				rec := doRequest(b)
+				So(rec.Code, ShouldEqual, http.StatusConflict)
+
+				var response model.UpdateError
+				So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+				So(response.Failed, ShouldEqual, "update dimensions")
+				So(response.RolledBack, ShouldResemble, []string{"update dataset"})
+				So(response.RollbackFailed, ShouldResemble, []string{"update version"})
+				So(mockDatasetClient.PutVersionCalls()[1].IfMatch, ShouldEqual, "version-updated-etag")
+			})
 		})
 	})
 }
@@ -225,17 +549,17 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 			florenceToken := "testuser"
 
 			datasetClient := &DatasetClientMock{
-				PutMetadataFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) error {
+				PutMetadataFunc: func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
 					if userAuthToken != florenceToken || serviceAuthToken != "" {
-						return errors.New("Function called with unexpected tokens")
+						return "", errors.New("Function called with unexpected tokens")
 					}
 					if collectionID != mockCollectionId || datasetID != mockDatasetId || edition != mockEdition || version != mockVersionNumber {
-						return errors.New("Function called with unexpected parameters")
+						return "", errors.New("Function called with unexpected parameters")
 					}
 					if versionEtag != etag {
-						return errors.New("Function called with invalid version etag")
+						return "", errors.New("Function called with invalid version etag")
 					}
-					return nil
+					return "", nil
 				},
 			}
 
@@ -255,7 +579,7 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 			}
 
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(PutEditableMetadata(datasetClient, zebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(PutEditableMetadata(datasetClient, zebedeeClient, ""))
 
 			rec := httptest.NewRecorder()
 
@@ -271,7 +595,7 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 
 				Convey("Then we receive a 400 response", func() {
 					So(rec.Code, ShouldEqual, http.StatusBadRequest)
-					So(rec.Body.String(), ShouldEqual, "no user access token header set\n")
+					So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "missing_user_access_token", Message: "no user access token header set"})
 
 					So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
 					So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
@@ -286,7 +610,7 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 
 				Convey("Then we receive a 400 response", func() {
 					So(rec.Code, ShouldEqual, http.StatusBadRequest)
-					So(rec.Body.String(), ShouldEqual, "no collection ID header set\n")
+					So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "missing_collection_id", Message: "no collection ID header set"})
 
 					So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
 					So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
@@ -306,7 +630,7 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 
 						Convey("Then we receive a 500 response", func() {
 							So(rec.Code, ShouldEqual, http.StatusInternalServerError)
-							So(rec.Body.String(), ShouldEqual, "error updating metadata\n")
+							So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "upstream_error", Message: "error updating metadata", UpstreamService: "dataset-api"})
 
 							So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 1)
 							So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
@@ -315,6 +639,175 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 					})
 				})
 
+				Convey("And the metadata is invalid", func() {
+					invalid := metadata
+					invalid.Dataset.Keywords = &[]string{"one", " "}
+					invalidBody, _ := json.Marshal(invalid)
+					req := httptest.NewRequest("PUT", url, bytes.NewBuffer(invalidBody))
+					req.Header.Set("Collection-Id", mockCollectionId)
+					req.Header.Set("X-Florence-Token", florenceToken)
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then we receive a 422 response pointing at the invalid field", func() {
+							So(rec.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+							var response model.ValidationError
+							So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+							So(response.Errors, ShouldResemble, []model.FieldError{{Pointer: "/dataset/keywords/1", Message: "must not be blank"}})
+
+							So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
+						})
+					})
+				})
+
+				Convey("And the version has been changed by another user", func() {
+					currentVersion := metadata.Version
+					currentVersion.ReleaseDate = "2021-01-01T00:00:00.000Z"
+					currentDataset := metadata.Dataset
+					currentDataset.Title = "changed title"
+
+					datasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+						return "", testStatusError{http.StatusConflict}
+					}
+					datasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+						return currentVersion, datasetclient.ResponseHeaders{ETag: "current-etag"}, nil
+					}
+					datasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+						return datasetclient.Dataset{ID: datasetID, Next: &currentDataset}, nil
+					}
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then we receive a 409 response with the current etag and the changed fields", func() {
+							So(rec.Code, ShouldEqual, http.StatusConflict)
+
+							var conflict model.MetadataConflict
+							So(json.Unmarshal(rec.Body.Bytes(), &conflict), ShouldBeNil)
+							So(conflict.Code, ShouldEqual, "conflict")
+							So(conflict.UpstreamStatus, ShouldEqual, http.StatusConflict)
+							So(conflict.CurrentEtag, ShouldEqual, "current-etag")
+							So(conflict.Conflicts, ShouldResemble, []model.FieldConflict{
+								{Field: "release_date", Submitted: json.RawMessage("null"), Current: json.RawMessage(`"2021-01-01T00:00:00.000Z"`)},
+								{Field: "title", Submitted: json.RawMessage(`"dataset title"`), Current: json.RawMessage(`"changed title"`)},
+							})
+
+							So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+							So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+						})
+					})
+				})
+
+				Convey("And the version has been changed by another user since the client loaded it", func() {
+					original := metadata
+					submitted := metadata
+					submitted.Dataset.Title = "new title"
+					submitted.Original = &original
+					submittedBody, _ := json.Marshal(submitted)
+					req := httptest.NewRequest("PUT", url, bytes.NewBuffer(submittedBody))
+					req.Header.Set("Collection-Id", mockCollectionId)
+					req.Header.Set("X-Florence-Token", florenceToken)
+
+					currentVersion := metadata.Version
+					currentVersion.ReleaseDate = "2021-01-01T00:00:00.000Z"
+					datasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+						return "", testStatusError{http.StatusPreconditionFailed}
+					}
+					datasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+						return currentVersion, datasetclient.ResponseHeaders{ETag: "current-etag"}, nil
+					}
+					datasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+						return datasetclient.Dataset{ID: datasetID, Next: &metadata.Dataset}, nil
+					}
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then only the fields changed by the other user are reported", func() {
+							So(rec.Code, ShouldEqual, http.StatusPreconditionFailed)
+
+							var conflict model.MetadataConflict
+							So(json.Unmarshal(rec.Body.Bytes(), &conflict), ShouldBeNil)
+							So(conflict.Conflicts, ShouldResemble, []model.FieldConflict{
+								{Field: "release_date", Submitted: json.RawMessage("null"), Current: json.RawMessage(`"2021-01-01T00:00:00.000Z"`)},
+							})
+						})
+					})
+				})
+
+				Convey("And the dataset API returns a conflict that is not caused by the version etag", func() {
+					datasetClient.PutMetadataFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID, edition, version string, editableMetadata datasetclient.EditableMetadata, versionEtag string) (string, error) {
+						return "", testStatusError{http.StatusConflict}
+					}
+					datasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+						return metadata.Version, datasetclient.ResponseHeaders{ETag: etag}, nil
+					}
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then the upstream error is passed through without a list of conflicts", func() {
+							So(rec.Code, ShouldEqual, http.StatusConflict)
+							So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "conflict", Message: "error updating metadata", UpstreamService: "dataset-api", UpstreamStatus: http.StatusConflict})
+							So(len(datasetClient.GetDatasetCurrentAndNextCalls()), ShouldEqual, 0)
+						})
+					})
+				})
+
+				Convey("And the request is a dry run", func() {
+					currentDataset := metadata.Dataset
+					currentDataset.Title = "current title"
+					datasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, downloadServiceAuthToken, collectionID, datasetID, edition, version string) (datasetclient.Version, datasetclient.ResponseHeaders, error) {
+						return metadata.Version, datasetclient.ResponseHeaders{ETag: "current-etag"}, nil
+					}
+					datasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, userAuthToken, serviceAuthToken, collectionID, datasetID string) (datasetclient.Dataset, error) {
+						return datasetclient.Dataset{ID: datasetID, Next: &currentDataset}, nil
+					}
+					req := httptest.NewRequest("PUT", url+"?dry_run=true", bytes.NewBuffer(body))
+					req.Header.Set("Collection-Id", mockCollectionId)
+					req.Header.Set("X-Florence-Token", florenceToken)
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then we receive the planned changes without anything being written", func() {
+							So(rec.Code, ShouldEqual, http.StatusOK)
+
+							var dryRun model.MetadataDryRun
+							So(json.Unmarshal(rec.Body.Bytes(), &dryRun), ShouldBeNil)
+							So(dryRun, ShouldResemble, model.MetadataDryRun{
+								DryRun:      true,
+								VersionEtag: "current-etag",
+								Changes: []model.FieldChange{
+									{Field: "title", Current: json.RawMessage(`"current title"`), Planned: json.RawMessage(`"dataset title"`)},
+								},
+							})
+
+							So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
+							So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+							So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+						})
+					})
+				})
+
+				Convey("And the dry run query parameter is invalid", func() {
+					req := httptest.NewRequest("PUT", url+"?dry_run=maybe", bytes.NewBuffer(body))
+					req.Header.Set("Collection-Id", mockCollectionId)
+					req.Header.Set("X-Florence-Token", florenceToken)
+
+					Convey("When a PUT metadata request is made", func() {
+						router.ServeHTTP(rec, req)
+
+						Convey("Then we receive a 400 response", func() {
+							So(rec.Code, ShouldEqual, http.StatusBadRequest)
+							So(decodeErrorResponse(rec.Body.String()), ShouldResemble, model.ErrorResponse{Code: "invalid_query_parameter", Message: "invalid dry_run query parameter: maybe"})
+							So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
+						})
+					})
+				})
+
 				Convey("When a PUT metadata request is made", func() {
 					router.ServeHTTP(rec, req)
 
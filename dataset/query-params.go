@@ -0,0 +1,67 @@
+package dataset
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// getIntQueryParam reads a non-negative integer query parameter, returning defaultValue if it is not set
+func getIntQueryParam(req *http.Request, key string, defaultValue int) (int, error) {
+	value := req.URL.Query().Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid %s query parameter: %s", key, value)
+	}
+	return i, nil
+}
+
+// getBoolQueryParam reads a true or false query parameter, returning false if it is not set
+func getBoolQueryParam(req *http.Request, key string) (bool, error) {
+	value := req.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s query parameter: %s", key, value)
+	}
+	return b, nil
+}
+
+// getListQueryParam reads a comma separated query parameter, returning an error if it contains a value that is not allowed
+func getListQueryParam(req *http.Request, key string, allowed map[string]bool) ([]string, error) {
+	value := req.URL.Query().Get(key)
+	if value == "" {
+		return []string{}, nil
+	}
+
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if !allowed[v] {
+			return nil, fmt.Errorf("invalid %s query parameter: %s", key, v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// getEnumQueryParam reads a query parameter that must be one of the allowed values, returning defaultValue if it is not set
+func getEnumQueryParam(req *http.Request, key, defaultValue string, allowed map[string]bool) (string, error) {
+	value := req.URL.Query().Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	if !allowed[value] {
+		return "", fmt.Errorf("invalid %s query parameter: %s", key, value)
+	}
+	return value, nil
+}
@@ -0,0 +1,181 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// metadataValidation collects the field errors found in an edit metadata request
+type metadataValidation struct {
+	errors []model.FieldError
+}
+
+// validateEditMetadata checks the dataset, version and dimension fields of an edit metadata request,
+// returning an error for each invalid field identified by a JSON pointer into the request body
+func validateEditMetadata(body model.EditMetadata) []model.FieldError {
+	v := &metadataValidation{errors: []model.FieldError{}}
+
+	d := body.Dataset
+	v.required("/dataset/title", d.Title)
+	if d.Keywords != nil {
+		v.keywords("/dataset/keywords", *d.Keywords)
+	}
+	v.qmi("/dataset/qmi", d.QMI)
+	if d.Contacts != nil {
+		v.contacts("/dataset/contacts", *d.Contacts)
+	}
+
+	version := body.Version
+	v.date("/version/release_date", version.ReleaseDate)
+	if version.Alerts != nil {
+		v.alerts("/version/alerts", *version.Alerts)
+	}
+	if version.UsageNotes != nil {
+		v.usageNotes("/version/usage_notes", *version.UsageNotes)
+	}
+	v.latestChanges("/version/latest_changes", version.LatestChanges)
+
+	for i, dim := range body.Dimensions {
+		v.required(fmt.Sprintf("/dimensions/%d/label", i), dim.Label)
+	}
+
+	return v.errors
+}
+
+// validateMetadataPatch checks that each field of a metadata patch is editable and holds a valid value,
+// returning an error for each invalid field identified by a JSON pointer into the patch
+func validateMetadataPatch(pointer string, patch map[string]json.RawMessage) []model.FieldError {
+	v := &metadataValidation{errors: []model.FieldError{}}
+
+	names := make([]string, 0, len(patch))
+	for name := range patch {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !mapper.EditableMetadataFields[name] {
+			v.add(pointer+"/"+name, "is not an editable metadata field")
+			continue
+		}
+		if _, err := mapper.PatchEditableMetadata(datasetclient.EditableMetadata{}, map[string]json.RawMessage{name: patch[name]}); err != nil {
+			v.add(pointer+"/"+name, "has a value of the wrong type")
+		}
+	}
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+
+	m, _ := mapper.PatchEditableMetadata(datasetclient.EditableMetadata{}, patch)
+	if _, ok := patch["title"]; ok {
+		v.required(pointer+"/title", m.Title)
+	}
+	v.keywords(pointer+"/keywords", m.Keywords)
+	if m.QMI != nil {
+		v.qmi(pointer+"/qmi", *m.QMI)
+	}
+	v.contacts(pointer+"/contacts", m.Contacts)
+	v.date(pointer+"/release_date", m.ReleaseDate)
+	if m.Alerts != nil {
+		v.alerts(pointer+"/alerts", *m.Alerts)
+	}
+	if m.UsageNotes != nil {
+		v.usageNotes(pointer+"/usage_notes", *m.UsageNotes)
+	}
+	if m.LatestChanges != nil {
+		v.latestChanges(pointer+"/latest_changes", *m.LatestChanges)
+	}
+
+	return v.errors
+}
+
+func (v *metadataValidation) add(pointer, message string) {
+	v.errors = append(v.errors, model.FieldError{Pointer: pointer, Message: message})
+}
+
+// required adds an error if the value is empty or only whitespace
+func (v *metadataValidation) required(pointer, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(pointer, "must not be blank")
+	}
+}
+
+// date adds an error if the value is set and is not an RFC 3339 timestamp, as used by the dataset API
+func (v *metadataValidation) date(pointer, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		v.add(pointer, "must be an RFC 3339 date, e.g. 2021-01-31T00:00:00.000Z")
+	}
+}
+
+func (v *metadataValidation) keywords(pointer string, keywords []string) {
+	for i, keyword := range keywords {
+		v.required(fmt.Sprintf("%s/%d", pointer, i), keyword)
+	}
+}
+
+func (v *metadataValidation) qmi(pointer string, qmi datasetclient.Publication) {
+	if qmi.URL != "" && !isValidHRef(qmi.URL) {
+		v.add(pointer+"/href", "must be an http(s) URL or a path starting with /")
+	}
+}
+
+func (v *metadataValidation) contacts(pointer string, contacts []datasetclient.Contact) {
+	v.errors = append(v.errors, validateContacts(pointer, contacts)...)
+}
+
+func (v *metadataValidation) alerts(pointer string, alerts []datasetclient.Alert) {
+	for i, alert := range alerts {
+		v.date(fmt.Sprintf("%s/%d/date", pointer, i), alert.Date)
+	}
+}
+
+func (v *metadataValidation) usageNotes(pointer string, notes []datasetclient.UsageNote) {
+	for i, note := range notes {
+		v.required(fmt.Sprintf("%s/%d/title", pointer, i), note.Title)
+	}
+}
+
+func (v *metadataValidation) latestChanges(pointer string, changes []datasetclient.Change) {
+	for i, change := range changes {
+		v.required(fmt.Sprintf("%s/%d/name", pointer, i), change.Name)
+	}
+}
+
+// isValidHRef reports whether the value is an absolute http(s) URL or a path on the ONS website
+func isValidHRef(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return strings.HasPrefix(u.Path, "/")
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// writeValidationError writes a 422 response listing every invalid field
+func writeValidationError(w http.ResponseWriter, req *http.Request, fieldErrors []model.FieldError, logInfo map[string]interface{}) {
+	logData := log.Data{"validation_errors": fieldErrors}
+	for k, val := range logInfo {
+		logData[k] = val
+	}
+	log.Warn(req.Context(), "metadata failed validation", logData)
+
+	writeJSONError(w, req, http.StatusUnprocessableEntity, model.ValidationError{
+		ErrorResponse: newErrorResponse(req, errCodeValidationFailed, "metadata failed validation"),
+		Errors:        fieldErrors,
+	})
+}
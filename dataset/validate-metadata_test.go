@@ -0,0 +1,113 @@
+package dataset
+
+import (
+	"encoding/json"
+	"testing"
+
+	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitValidateEditMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("test validateEditMetadata", t, func() {
+		valid := model.EditMetadata{
+			Dataset: datasetclient.DatasetDetails{
+				Title:    "dataset title",
+				Keywords: &[]string{"one", "two"},
+				QMI:      datasetclient.Publication{URL: "https://www.ons.gov.uk/qmi"},
+				Contacts: &[]datasetclient.Contact{{Name: "contact", Email: "contact@ons.gov.uk", Telephone: "+44 (0)1633 123456"}},
+			},
+			Version: datasetclient.Version{
+				ReleaseDate:   "2021-01-31T00:00:00.000Z",
+				Alerts:        &[]datasetclient.Alert{{Date: "2021-02-01T09:30:00Z", Description: "alert"}},
+				UsageNotes:    &[]datasetclient.UsageNote{{Title: "note", Note: "note text"}},
+				LatestChanges: []datasetclient.Change{{Name: "change", Description: "change description"}},
+			},
+			Dimensions: []datasetclient.VersionDimension{{Name: "geography", Label: "Geography"}},
+		}
+
+		Convey("returns no errors for valid metadata", func() {
+			So(validateEditMetadata(valid), ShouldBeEmpty)
+		})
+
+		Convey("accepts a QMI path on the ONS website", func() {
+			valid.Dataset.QMI.URL = "/methodology/qmi"
+			So(validateEditMetadata(valid), ShouldBeEmpty)
+		})
+
+		Convey("returns every invalid field with a pointer to it", func() {
+			invalid := model.EditMetadata{
+				Dataset: datasetclient.DatasetDetails{
+					Title:    " ",
+					Keywords: &[]string{"one", ""},
+					QMI:      datasetclient.Publication{URL: "ftp://ons.gov.uk/qmi"},
+					Contacts: &[]datasetclient.Contact{{Telephone: "not a number"}, {Name: "contact", Email: "Contact <contact@ons.gov.uk>"}},
+				},
+				Version: datasetclient.Version{
+					ReleaseDate:   "31 January 2021",
+					Alerts:        &[]datasetclient.Alert{{Date: "2021-02-01"}},
+					UsageNotes:    &[]datasetclient.UsageNote{{Note: "note text"}},
+					LatestChanges: []datasetclient.Change{{Description: "change description"}},
+				},
+				Dimensions: []datasetclient.VersionDimension{{Name: "geography"}},
+			}
+
+			So(validateEditMetadata(invalid), ShouldResemble, []model.FieldError{
+				{Pointer: "/dataset/title", Message: "must not be blank"},
+				{Pointer: "/dataset/keywords/1", Message: "must not be blank"},
+				{Pointer: "/dataset/qmi/href", Message: "must be an http(s) URL or a path starting with /"},
+				{Pointer: "/dataset/contacts/0", Message: "must have a name or an email"},
+				{Pointer: "/dataset/contacts/0/telephone", Message: "must be a valid telephone number"},
+				{Pointer: "/dataset/contacts/1/email", Message: "must be a valid email address"},
+				{Pointer: "/version/release_date", Message: "must be an RFC 3339 date, e.g. 2021-01-31T00:00:00.000Z"},
+				{Pointer: "/version/alerts/0/date", Message: "must be an RFC 3339 date, e.g. 2021-01-31T00:00:00.000Z"},
+				{Pointer: "/version/usage_notes/0/title", Message: "must not be blank"},
+				{Pointer: "/version/latest_changes/0/name", Message: "must not be blank"},
+				{Pointer: "/dimensions/0/label", Message: "must not be blank"},
+			})
+		})
+	})
+}
+
+func TestUnitValidateMetadataPatch(t *testing.T) {
+	t.Parallel()
+
+	Convey("test validateMetadataPatch", t, func() {
+		Convey("returns no errors for a valid patch", func() {
+			patch := map[string]json.RawMessage{
+				"release_frequency": json.RawMessage(`"quarterly"`),
+				"contacts":          json.RawMessage(`[{"name":"contact","email":"contact@ons.gov.uk"}]`),
+				"license":           json.RawMessage(`null`),
+			}
+			So(validateMetadataPatch("/patch", patch), ShouldBeEmpty)
+		})
+
+		Convey("returns fields that cannot be patched or have the wrong type", func() {
+			patch := map[string]json.RawMessage{
+				"dimensions": json.RawMessage(`[]`),
+				"keywords":   json.RawMessage(`"one"`),
+			}
+			So(validateMetadataPatch("/patch", patch), ShouldResemble, []model.FieldError{
+				{Pointer: "/patch/dimensions", Message: "is not an editable metadata field"},
+				{Pointer: "/patch/keywords", Message: "has a value of the wrong type"},
+			})
+		})
+
+		Convey("validates the values of the patched fields", func() {
+			patch := map[string]json.RawMessage{
+				"title":        json.RawMessage(`""`),
+				"qmi":          json.RawMessage(`{"href":"not a url"}`),
+				"release_date": json.RawMessage(`"tomorrow"`),
+			}
+			So(validateMetadataPatch("/patch", patch), ShouldResemble, []model.FieldError{
+				{Pointer: "/patch/title", Message: "must not be blank"},
+				{Pointer: "/patch/qmi/href", Message: "must be an http(s) URL or a path starting with /"},
+				{Pointer: "/patch/release_date", Message: "must be an RFC 3339 date, e.g. 2021-01-31T00:00:00.000Z"},
+			})
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package dataset
+
+import (
+	"context"
+	"sync"
+)
+
+// forEachBounded calls fn for each index from 0 to n-1 using at most limit concurrent calls, and waits for the calls
+// to finish. No more calls are started once the context is done, so the number of calls started is returned; as calls
+// are started in order, these are the calls for the indexes below it
+func forEachBounded(ctx context.Context, n, limit int, fn func(i int)) int {
+	if limit < 1 {
+		limit = 1
+	}
+
+	workers := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	started := 0
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+		case workers <- struct{}{}:
+		}
+		// the context is checked after the select, as it picks at random when a worker is free and the context is done
+		if ctx.Err() != nil {
+			break
+		}
+
+		started++
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return started
+}
@@ -0,0 +1,69 @@
+package dataset
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitForEachBounded(t *testing.T) {
+	t.Parallel()
+
+	Convey("test forEachBounded", t, func() {
+
+		Convey("calls the function once for each index, with at most limit calls running at once", func() {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			called := make([]bool, 10)
+			release := make(chan struct{})
+
+			done := make(chan int)
+			go func() {
+				done <- forEachBounded(context.Background(), len(called), 3, func(i int) {
+					mu.Lock()
+					running++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					called[i] = true
+					mu.Unlock()
+
+					<-release
+
+					mu.Lock()
+					running--
+					mu.Unlock()
+				})
+			}()
+			close(release)
+
+			So(<-done, ShouldEqual, 10)
+			So(maxRunning, ShouldBeLessThanOrEqualTo, 3)
+			for i := range called {
+				So(called[i], ShouldBeTrue)
+			}
+		})
+
+		Convey("treats a limit below one as one", func() {
+			started := forEachBounded(context.Background(), 2, 0, func(i int) {})
+
+			So(started, ShouldEqual, 2)
+		})
+
+		Convey("starts no more calls once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			var called []int
+			started := forEachBounded(ctx, 5, 1, func(i int) {
+				called = append(called, i)
+				if i == 1 {
+					cancel()
+				}
+			})
+
+			So(started, ShouldEqual, 2)
+			So(called, ShouldResemble, []int{0, 1})
+		})
+	})
+}
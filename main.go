@@ -7,11 +7,12 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	"github.com/ONSdigital/dp-api-clients-go/v2/health"
 	"github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	dpnethttp "github.com/ONSdigital/dp-net/http"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/checks"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/dataset"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/config"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/routes"
@@ -57,12 +58,33 @@ func main() {
 	apiRouterCli := health.NewClient("api-router", cfg.APIRouterURL)
 	dc := dataset.NewWithHealthClient(apiRouterCli)
 	zc := zebedee.NewWithHealthClient(apiRouterCli)
-	bc := topics.New(cfg.BabbageURL)
+	bc := topics.NewWithConfig(cfg.BabbageURL, topics.Config{
+		MaxRetries:              cfg.BabbageMaxRetries,
+		RetryBackoff:            cfg.BabbageRetryBackoff,
+		MaxRetryBackoff:         cfg.BabbageMaxRetryBackoff,
+		BreakerFailureThreshold: cfg.BabbageBreakerThreshold,
+		BreakerOpenTimeout:      cfg.BabbageBreakerOpenTimeout,
+	})
 
 	hc := healthcheck.New(versionInfo, cfg.HealthCheckCritialTimeout, cfg.HealthCheckInterval)
-	if err = hc.AddCheck("API router", apiRouterCli.Checker); err != nil {
-		log.Fatal(ctx, "failed to add dataset API checker", err)
-		os.Exit(1)
+
+	// the dataset API and zebedee are called through the API router but are checked directly so that the health
+	// check shows which of them is failing
+	dependencies := []struct {
+		name     string
+		checker  healthcheck.Checker
+		critical bool
+	}{
+		{"API router", apiRouterCli.Checker, true},
+		{"dataset API", health.NewClient("dataset-api", cfg.DatasetAPIURL).Checker, cfg.DatasetAPICritical},
+		{"Zebedee", health.NewClient("zebedee", cfg.ZebedeeURL).Checker, cfg.ZebedeeCritical},
+		{"Babbage", bc.Checker, cfg.BabbageCritical},
+	}
+	for _, d := range dependencies {
+		if err = hc.AddCheck(d.name, checks.WithCriticality(d.name, d.checker, d.critical)); err != nil {
+			log.Fatal(ctx, "failed to add health checker", err, log.Data{"check": d.name})
+			os.Exit(1)
+		}
 	}
 
 	router := mux.NewRouter()
@@ -9,8 +9,9 @@ import (
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
-// AllEditions maps dataset and editions response to editions list page model
-func AllEditions(ctx context.Context, dataset dataset.Dataset, editions []dataset.Edition, latestVersions map[string]string) model.EditionsPage {
+// AllEditions maps dataset and editions response to editions list page model. Editions whose latest version
+// could not be looked up have the reason set in lookupErrors
+func AllEditions(ctx context.Context, dataset dataset.Dataset, editions []dataset.Edition, latestVersions, lookupErrors map[string]string) model.EditionsPage {
 	var mappedEditions []model.Edition
 	for _, e := range editions {
 		var timeF string
@@ -28,6 +29,7 @@ func AllEditions(ctx context.Context, dataset dataset.Dataset, editions []datase
 			ID:          e.Edition,
 			Title:       e.Edition,
 			ReleaseDate: timeF,
+			LookupError: lookupErrors[e.Edition],
 		})
 	}
 
@@ -33,15 +33,17 @@ var mockedEditions = []dataset.Edition{
 
 var mockedLatestVersions = map[string]string{"edition-1": "2020-11-07T00:00:00.000Z", "edition-2": ""}
 
+var mockedLookupErrors = map[string]string{"edition-2": "failed to get latest version"}
+
 func TestUnitAllEditions(t *testing.T) {
 	t.Parallel()
 
-	expectedAllEditions := []model.Edition{{ID: "edition-1", Title: "edition-1", ReleaseDate: "07 November 2020"}, {ID: "edition-2", Title: "edition-2", ReleaseDate: ""}}
+	expectedAllEditions := []model.Edition{{ID: "edition-1", Title: "edition-1", ReleaseDate: "07 November 2020"}, {ID: "edition-2", Title: "edition-2", ReleaseDate: "", LookupError: "failed to get latest version"}}
 
 	expectedEditionsPage := model.EditionsPage{DatasetName: "Test title", Editions: expectedAllEditions}
 
 	Convey("test all editions maps correctly", t, func() {
-		mapped := AllEditions(ctx, mockedDataset, mockedEditions, mockedLatestVersions)
+		mapped := AllEditions(ctx, mockedDataset, mockedEditions, mockedLatestVersions, mockedLookupErrors)
 		So(mapped, ShouldResemble, expectedEditionsPage)
 	})
 }
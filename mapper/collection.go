@@ -0,0 +1,50 @@
+package mapper
+
+import (
+	"github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// CollectionOverview maps a zebedee collection to the collection overview, using the dataset titles looked up from
+// the dataset API. Datasets whose title could not be looked up keep the title zebedee has and have the reason set in
+// lookupErrors
+func CollectionOverview(c zebedee.Collection, titles, lookupErrors map[string]string) model.CollectionOverview {
+	overview := model.CollectionOverview{
+		ID:              c.ID,
+		Name:            c.Name,
+		ApprovalStatus:  c.ApprovalStatus,
+		Datasets:        []model.CollectionDataset{},
+		DatasetVersions: []model.CollectionDatasetVersion{},
+	}
+
+	for _, d := range c.Datasets {
+		overview.Datasets = append(overview.Datasets, model.CollectionDataset{
+			ID:           d.ID,
+			Title:        collectionItemTitle(d, titles),
+			State:        d.State,
+			LastEditedBy: d.LastEditedBy,
+			LookupError:  lookupErrors[d.ID],
+		})
+	}
+
+	for _, v := range c.DatasetVersions {
+		overview.DatasetVersions = append(overview.DatasetVersions, model.CollectionDatasetVersion{
+			DatasetID:    v.ID,
+			DatasetTitle: collectionItemTitle(v, titles),
+			Edition:      v.Edition,
+			Version:      v.Version,
+			State:        v.State,
+			LastEditedBy: v.LastEditedBy,
+			LookupError:  lookupErrors[v.ID],
+		})
+	}
+
+	return overview
+}
+
+func collectionItemTitle(item zebedee.CollectionItem, titles map[string]string) string {
+	if title, ok := titles[item.ID]; ok && title != "" {
+		return title
+	}
+	return item.Title
+}
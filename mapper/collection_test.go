@@ -0,0 +1,58 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitCollectionOverview(t *testing.T) {
+	t.Parallel()
+
+	Convey("test CollectionOverview", t, func() {
+		c := zebedee.Collection{
+			ID:             "collection-1",
+			Name:           "Collection one",
+			ApprovalStatus: "NOT_STARTED",
+			Datasets: []zebedee.CollectionItem{
+				{ID: "dataset-1", State: "InProgress", LastEditedBy: "a@ons.gov.uk", Title: "zebedee title 1"},
+			},
+			DatasetVersions: []zebedee.CollectionItem{
+				{ID: "dataset-1", State: "Complete", LastEditedBy: "b@ons.gov.uk", Edition: "2021", Version: "2"},
+				{ID: "dataset-2", State: "Reviewed", LastEditedBy: "c@ons.gov.uk", Title: "zebedee title 2", Edition: "time-series", Version: "1"},
+			},
+		}
+
+		Convey("uses the titles from the dataset API, falling back to zebedee's title for failed lookups", func() {
+			titles := map[string]string{"dataset-1": "Dataset one"}
+			lookupErrors := map[string]string{"dataset-2": "failed to get dataset"}
+
+			overview := CollectionOverview(c, titles, lookupErrors)
+
+			So(overview, ShouldResemble, model.CollectionOverview{
+				ID:             "collection-1",
+				Name:           "Collection one",
+				ApprovalStatus: "NOT_STARTED",
+				Datasets: []model.CollectionDataset{
+					{ID: "dataset-1", Title: "Dataset one", State: "InProgress", LastEditedBy: "a@ons.gov.uk"},
+				},
+				DatasetVersions: []model.CollectionDatasetVersion{
+					{DatasetID: "dataset-1", DatasetTitle: "Dataset one", Edition: "2021", Version: "2", State: "Complete", LastEditedBy: "b@ons.gov.uk"},
+					{DatasetID: "dataset-2", DatasetTitle: "zebedee title 2", Edition: "time-series", Version: "1", State: "Reviewed", LastEditedBy: "c@ons.gov.uk", LookupError: "failed to get dataset"},
+				},
+			})
+		})
+
+		Convey("returns empty lists for an empty collection", func() {
+			overview := CollectionOverview(zebedee.Collection{ID: "empty"}, nil, nil)
+
+			So(overview.Datasets, ShouldNotBeNil)
+			So(overview.Datasets, ShouldBeEmpty)
+			So(overview.DatasetVersions, ShouldNotBeNil)
+			So(overview.DatasetVersions, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,214 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// EditableMetadataConflicts returns the editable metadata fields that have been changed by someone else since the client loaded them,
+// along with the submitted value and the value currently held by the dataset API. A field changed to the submitted value is not a conflict
+func EditableMetadataConflicts(loaded, submitted, current dataset.EditableMetadata) []model.FieldConflict {
+	submittedFields := jsonFields(submitted)
+
+	conflicts := []model.FieldConflict{}
+	for _, field := range diffFields(loaded, current) {
+		submittedValue := nullIfEmpty(submittedFields[field.name])
+		if bytes.Equal(submittedValue, field.b) {
+			continue
+		}
+		conflicts = append(conflicts, model.FieldConflict{
+			Field:     field.name,
+			Submitted: submittedValue,
+			Current:   field.b,
+		})
+	}
+	return conflicts
+}
+
+// EditableMetadataChanges compares the editable metadata currently held by the dataset API against the metadata planned to replace it
+func EditableMetadataChanges(current, planned dataset.EditableMetadata) []model.FieldChange {
+	changes := []model.FieldChange{}
+	for _, field := range diffFields(current, planned) {
+		changes = append(changes, model.FieldChange{
+			Field:   field.name,
+			Current: field.a,
+			Planned: field.b,
+		})
+	}
+	return changes
+}
+
+// DimensionConflicts compares the submitted dimensions against the dimensions currently held on the instance
+func DimensionConflicts(submitted, current []dataset.VersionDimension) []model.FieldConflict {
+	conflicts := []model.FieldConflict{}
+	for _, field := range diffFields(dimensionsDiffFields{toDimensionDiffFields(submitted)}, dimensionsDiffFields{toDimensionDiffFields(current)}) {
+		conflicts = append(conflicts, model.FieldConflict{
+			Field:     field.name,
+			Submitted: field.a,
+			Current:   field.b,
+		})
+	}
+	return conflicts
+}
+
+// MetadataDiff compares the draft dataset and version against the published dataset and the latest published version
+func MetadataDiff(published, draft *dataset.DatasetDetails, publishedVersion, draftVersion dataset.Version) model.MetadataDiff {
+	metadataDiff := model.MetadataDiff{
+		Edition:          draftVersion.Edition,
+		Version:          draftVersion.Version,
+		PublishedEdition: publishedVersion.Edition,
+		PublishedVersion: publishedVersion.Version,
+		DatasetChanges:   toFieldDiffs(diffFields(newDatasetDiffFields(published), newDatasetDiffFields(draft))),
+		VersionChanges:   toFieldDiffs(diffFields(newVersionDiffFields(publishedVersion), newVersionDiffFields(draftVersion))),
+	}
+	if draft != nil {
+		metadataDiff.DatasetID = draft.ID
+	}
+	return metadataDiff
+}
+
+// datasetDiffFields holds the dataset fields compared by MetadataDiff
+type datasetDiffFields struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Keywords    []string          `json:"keywords"`
+	Contacts    []dataset.Contact `json:"contacts"`
+}
+
+// versionDiffFields holds the version fields compared by MetadataDiff
+type versionDiffFields struct {
+	Dimensions    []dimensionDiffFields `json:"dimensions"`
+	UsageNotes    []dataset.UsageNote   `json:"usage_notes"`
+	Alerts        []dataset.Alert       `json:"alerts"`
+	LatestChanges []dataset.Change      `json:"latest_changes"`
+}
+
+// dimensionDiffFields holds the dimension fields compared by MetadataDiff. Links are left out as they always differ between versions
+type dimensionDiffFields struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// dimensionsDiffFields holds the instance dimensions compared by DimensionConflicts
+type dimensionsDiffFields struct {
+	Dimensions []dimensionDiffFields `json:"dimensions"`
+}
+
+func newDatasetDiffFields(d *dataset.DatasetDetails) datasetDiffFields {
+	fields := datasetDiffFields{
+		Keywords: []string{},
+		Contacts: []dataset.Contact{},
+	}
+	if d == nil {
+		return fields
+	}
+
+	fields.Title = d.Title
+	fields.Description = d.Description
+	if d.Keywords != nil {
+		fields.Keywords = append(fields.Keywords, *d.Keywords...)
+	}
+	if d.Contacts != nil {
+		fields.Contacts = append(fields.Contacts, *d.Contacts...)
+	}
+	return fields
+}
+
+func newVersionDiffFields(v dataset.Version) versionDiffFields {
+	fields := versionDiffFields{
+		Dimensions:    toDimensionDiffFields(v.Dimensions),
+		UsageNotes:    []dataset.UsageNote{},
+		Alerts:        []dataset.Alert{},
+		LatestChanges: append([]dataset.Change{}, v.LatestChanges...),
+	}
+	if v.UsageNotes != nil {
+		fields.UsageNotes = append(fields.UsageNotes, *v.UsageNotes...)
+	}
+	if v.Alerts != nil {
+		fields.Alerts = append(fields.Alerts, *v.Alerts...)
+	}
+	return fields
+}
+
+func toDimensionDiffFields(dims []dataset.VersionDimension) []dimensionDiffFields {
+	fields := []dimensionDiffFields{}
+	for _, dim := range dims {
+		fields = append(fields, dimensionDiffFields{
+			ID:          dim.ID,
+			Name:        dim.Name,
+			Label:       dim.Label,
+			Description: dim.Description,
+		})
+	}
+	return fields
+}
+
+func toFieldDiffs(diffs []fieldDiff) []model.FieldDiff {
+	fieldDiffs := []model.FieldDiff{}
+	for _, diff := range diffs {
+		fieldDiffs = append(fieldDiffs, model.FieldDiff{
+			Field:     diff.name,
+			Published: diff.a,
+			Draft:     diff.b,
+		})
+	}
+	return fieldDiffs
+}
+
+type fieldDiff struct {
+	name string
+	a    json.RawMessage
+	b    json.RawMessage
+}
+
+// diffFields compares the JSON representation of two values field by field, returning the fields that differ ordered by name.
+// Fields omitted from one of the values are treated as null
+func diffFields(a, b interface{}) []fieldDiff {
+	aFields := jsonFields(a)
+	bFields := jsonFields(b)
+
+	names := map[string]bool{}
+	for name := range aFields {
+		names[name] = true
+	}
+	for name := range bFields {
+		names[name] = true
+	}
+
+	var diffs []fieldDiff
+	for name := range names {
+		aValue, bValue := nullIfEmpty(aFields[name]), nullIfEmpty(bFields[name])
+		if bytes.Equal(aValue, bValue) {
+			continue
+		}
+		diffs = append(diffs, fieldDiff{name: name, a: aValue, b: bValue})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].name < diffs[j].name
+	})
+	return diffs
+}
+
+func jsonFields(v interface{}) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(b, &fields)
+	return fields
+}
+
+func nullIfEmpty(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return json.RawMessage("null")
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+package mapper
+
+import (
+	"encoding/json"
+	"testing"
+
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitEditableMetadataConflicts(t *testing.T) {
+	t.Parallel()
+
+	Convey("test EditableMetadataConflicts", t, func() {
+		loaded := dataset.EditableMetadata{
+			Title:       "title",
+			Description: "description",
+			Keywords:    []string{"one", "two"},
+		}
+		submitted := loaded
+		submitted.Title = "new title"
+
+		Convey("returns no conflicts when nobody else has changed the metadata", func() {
+			So(EditableMetadataConflicts(loaded, submitted, loaded), ShouldResemble, []model.FieldConflict{})
+		})
+
+		Convey("returns the fields changed, added and removed by someone else ordered by name", func() {
+			current := dataset.EditableMetadata{
+				Title:            "title",
+				Description:      "changed description",
+				ReleaseFrequency: "monthly",
+			}
+
+			So(EditableMetadataConflicts(loaded, submitted, current), ShouldResemble, []model.FieldConflict{
+				{Field: "description", Submitted: json.RawMessage(`"description"`), Current: json.RawMessage(`"changed description"`)},
+				{Field: "keywords", Submitted: json.RawMessage(`["one","two"]`), Current: json.RawMessage("null")},
+				{Field: "release_frequency", Submitted: json.RawMessage("null"), Current: json.RawMessage(`"monthly"`)},
+			})
+		})
+
+		Convey("does not return fields changed to the submitted value", func() {
+			current := loaded
+			current.Title = "new title"
+
+			So(EditableMetadataConflicts(loaded, submitted, current), ShouldResemble, []model.FieldConflict{})
+		})
+	})
+}
+
+func TestUnitEditableMetadataChanges(t *testing.T) {
+	t.Parallel()
+
+	Convey("test EditableMetadataChanges", t, func() {
+		current := dataset.EditableMetadata{
+			Title:       "title",
+			Description: "description",
+		}
+
+		Convey("returns no changes when the planned metadata is the same", func() {
+			So(EditableMetadataChanges(current, current), ShouldResemble, []model.FieldChange{})
+		})
+
+		Convey("returns the current and planned value of each changed field", func() {
+			planned := dataset.EditableMetadata{
+				Title:       "new title",
+				Description: "description",
+				Keywords:    []string{"one"},
+			}
+
+			So(EditableMetadataChanges(current, planned), ShouldResemble, []model.FieldChange{
+				{Field: "keywords", Current: json.RawMessage("null"), Planned: json.RawMessage(`["one"]`)},
+				{Field: "title", Current: json.RawMessage(`"title"`), Planned: json.RawMessage(`"new title"`)},
+			})
+		})
+	})
+}
+
+func TestUnitMetadataDiff(t *testing.T) {
+	t.Parallel()
+
+	Convey("test MetadataDiff", t, func() {
+		published := &dataset.DatasetDetails{
+			ID:          "cpih01",
+			Title:       "title",
+			Description: "description",
+			Keywords:    &[]string{"one"},
+		}
+		publishedVersion := dataset.Version{
+			Edition: "time-series",
+			Version: 1,
+			Dimensions: []dataset.VersionDimension{
+				{ID: "geography", Name: "geography", Label: "Geography", Description: "description", Links: dataset.Links{Self: dataset.Link{URL: "/v1"}}},
+			},
+			UsageNotes: &[]dataset.UsageNote{{Title: "note", Note: "note text"}},
+		}
+
+		Convey("returns no changes when the draft matches the published metadata", func() {
+			draftVersion := publishedVersion
+			draftVersion.Version = 2
+			draftVersion.Dimensions = []dataset.VersionDimension{
+				{ID: "geography", Name: "geography", Label: "Geography", Description: "description", Links: dataset.Links{Self: dataset.Link{URL: "/v2"}}},
+			}
+
+			So(MetadataDiff(published, published, publishedVersion, draftVersion), ShouldResemble, model.MetadataDiff{
+				DatasetID:        "cpih01",
+				Edition:          "time-series",
+				Version:          2,
+				PublishedEdition: "time-series",
+				PublishedVersion: 1,
+				DatasetChanges:   []model.FieldDiff{},
+				VersionChanges:   []model.FieldDiff{},
+			})
+		})
+
+		Convey("returns the changed dataset and version fields", func() {
+			draft := *published
+			draft.Title = "new title"
+			draft.Keywords = nil
+			draftVersion := publishedVersion
+			draftVersion.Version = 2
+			draftVersion.UsageNotes = nil
+			draftVersion.Alerts = &[]dataset.Alert{{Description: "correction", Type: "correction"}}
+
+			diff := MetadataDiff(published, &draft, publishedVersion, draftVersion)
+			So(diff.DatasetChanges, ShouldResemble, []model.FieldDiff{
+				{Field: "keywords", Published: json.RawMessage(`["one"]`), Draft: json.RawMessage(`[]`)},
+				{Field: "title", Published: json.RawMessage(`"title"`), Draft: json.RawMessage(`"new title"`)},
+			})
+			So(diff.VersionChanges, ShouldResemble, []model.FieldDiff{
+				{Field: "alerts", Published: json.RawMessage(`[]`), Draft: json.RawMessage(`[{"date":"","description":"correction","type":"correction"}]`)},
+				{Field: "usage_notes", Published: json.RawMessage(`[{"note":"note text","title":"note"}]`), Draft: json.RawMessage(`[]`)},
+			})
+		})
+
+		Convey("reports every draft field as a change when nothing has been published", func() {
+			diff := MetadataDiff(nil, published, dataset.Version{}, publishedVersion)
+			So(diff.PublishedVersion, ShouldEqual, 0)
+			So(len(diff.DatasetChanges), ShouldEqual, 3)
+			So(len(diff.VersionChanges), ShouldEqual, 2)
+		})
+	})
+}
+
+func TestUnitDimensionConflicts(t *testing.T) {
+	t.Parallel()
+
+	Convey("test DimensionConflicts", t, func() {
+		current := []dataset.VersionDimension{{ID: "dim001", Name: "geography", Label: "Geography", Links: dataset.Links{Self: dataset.Link{URL: "self"}}}}
+
+		Convey("returns no conflicts when the dimensions are the same", func() {
+			submitted := []dataset.VersionDimension{{ID: "dim001", Name: "geography", Label: "Geography"}}
+			So(DimensionConflicts(submitted, current), ShouldBeEmpty)
+		})
+
+		Convey("returns the submitted and current dimensions when they differ", func() {
+			submitted := []dataset.VersionDimension{{ID: "dim001", Name: "geography", Label: "Area"}}
+			conflicts := DimensionConflicts(submitted, current)
+
+			So(conflicts, ShouldHaveLength, 1)
+			So(conflicts[0].Field, ShouldEqual, "dimensions")
+			So(string(conflicts[0].Submitted), ShouldEqual, `[{"id":"dim001","name":"geography","label":"Area","description":""}]`)
+			So(string(conflicts[0].Current), ShouldEqual, `[{"id":"dim001","name":"geography","label":"Geography","description":""}]`)
+		})
+	})
+}
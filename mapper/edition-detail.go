@@ -0,0 +1,38 @@
+package mapper
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// EditionDetail maps the dataset, edition and its versions to the edition detail model
+func EditionDetail(ctx context.Context, d dataset.Dataset, edition dataset.Edition, versions dataset.VersionsList) model.EditionDetail {
+	versionsPage := AllVersions(ctx, d, edition, versions)
+
+	detail := model.EditionDetail{
+		ID:           edition.Edition,
+		DatasetID:    edition.Links.Dataset.ID,
+		DatasetName:  versionsPage.DatasetName,
+		State:        edition.State,
+		Links:        edition.Links,
+		VersionCount: len(versionsPage.Versions),
+	}
+	if detail.DatasetID == "" {
+		detail.DatasetID = d.ID
+	}
+
+	// versions are sorted newest first, so the first of each is the latest
+	for i := range versionsPage.Versions {
+		v := versionsPage.Versions[i]
+		if v.State == "published" && detail.LatestPublishedVersion == nil {
+			detail.LatestPublishedVersion = &v
+		}
+		if v.State != "published" && detail.LatestUnpublishedVersion == nil {
+			detail.LatestUnpublishedVersion = &v
+		}
+	}
+
+	return detail
+}
@@ -0,0 +1,65 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitEditionDetail(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	d := dataset.Dataset{
+		ID:   "test-dataset",
+		Next: &dataset.DatasetDetails{Title: "Test dataset"},
+	}
+	edition := dataset.Edition{
+		Edition: "2021",
+		State:   "edition-confirmed",
+		Links:   dataset.Links{Dataset: dataset.Link{ID: "test-dataset"}},
+	}
+
+	Convey("test EditionDetail", t, func() {
+		Convey("maps the latest published and unpublished versions", func() {
+			versions := dataset.VersionsList{Items: []dataset.Version{
+				{ID: "v1", Version: 1, State: "published", ReleaseDate: "2021-01-01T00:00:00Z"},
+				{ID: "v3", Version: 3, State: "edition-confirmed"},
+				{ID: "v2", Version: 2, State: "published", ReleaseDate: "2021-02-01T00:00:00Z"},
+			}}
+
+			detail := EditionDetail(ctx, d, edition, versions)
+
+			So(detail.ID, ShouldEqual, "2021")
+			So(detail.DatasetID, ShouldEqual, "test-dataset")
+			So(detail.DatasetName, ShouldEqual, "Test dataset")
+			So(detail.State, ShouldEqual, "edition-confirmed")
+			So(detail.VersionCount, ShouldEqual, 3)
+			So(detail.LatestPublishedVersion, ShouldResemble, &model.Version{ID: "v2", Title: "Version: 2 (published)", Version: 2, ReleaseDate: "01 February 2021", State: "published"})
+			So(detail.LatestUnpublishedVersion, ShouldResemble, &model.Version{ID: "v3", Title: "Version: 3", Version: 3, State: "edition-confirmed"})
+		})
+
+		Convey("leaves the latest versions unset when there are none in that state", func() {
+			versions := dataset.VersionsList{Items: []dataset.Version{
+				{ID: "v1", Version: 1, State: "published"},
+			}}
+
+			detail := EditionDetail(ctx, d, edition, versions)
+
+			So(detail.VersionCount, ShouldEqual, 1)
+			So(detail.LatestPublishedVersion, ShouldNotBeNil)
+			So(detail.LatestUnpublishedVersion, ShouldBeNil)
+		})
+
+		Convey("falls back to the dataset ID when the edition has no dataset link", func() {
+			detail := EditionDetail(ctx, d, dataset.Edition{Edition: "2021"}, dataset.VersionsList{})
+
+			So(detail.DatasetID, ShouldEqual, "test-dataset")
+			So(detail.VersionCount, ShouldEqual, 0)
+		})
+	})
+}
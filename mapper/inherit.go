@@ -0,0 +1,80 @@
+package mapper
+
+import (
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// Version fields that can be inherited from the previous published version
+const (
+	InheritedUsageNotes            = "usage_notes"
+	InheritedDimensionDescriptions = "dimension_descriptions"
+	InheritedLatestChanges         = "latest_changes"
+	InheritedAlerts                = "alerts"
+)
+
+// CarryOverFields are the inherited fields that are only copied when the caller selects them
+var CarryOverFields = map[string]bool{
+	InheritedLatestChanges: true,
+	InheritedAlerts:        true,
+}
+
+// InheritMetadata copies metadata from the previous published version into the fields of the version that are empty.
+// Usage notes and dimension descriptions are always inherited, latest changes and alerts only if included in carryOver.
+// It returns the updated version along with the fields that were inherited
+func InheritMetadata(v, published dataset.Version, carryOver []string) (dataset.Version, model.InheritedMetadata) {
+	inherited := model.InheritedMetadata{
+		Edition: published.Edition,
+		Version: published.Version,
+		Fields:  []string{},
+	}
+
+	if (v.UsageNotes == nil || len(*v.UsageNotes) == 0) && published.UsageNotes != nil && len(*published.UsageNotes) > 0 {
+		usageNotes := append([]dataset.UsageNote{}, *published.UsageNotes...)
+		v.UsageNotes = &usageNotes
+		inherited.Fields = append(inherited.Fields, InheritedUsageNotes)
+	}
+
+	if dims, ok := inheritDimensionDescriptions(v.Dimensions, published.Dimensions); ok {
+		v.Dimensions = dims
+		inherited.Fields = append(inherited.Fields, InheritedDimensionDescriptions)
+	}
+
+	selected := map[string]bool{}
+	for _, field := range carryOver {
+		selected[field] = true
+	}
+
+	if selected[InheritedLatestChanges] && len(v.LatestChanges) == 0 && len(published.LatestChanges) > 0 {
+		v.LatestChanges = append([]dataset.Change{}, published.LatestChanges...)
+		inherited.Fields = append(inherited.Fields, InheritedLatestChanges)
+	}
+
+	if selected[InheritedAlerts] && (v.Alerts == nil || len(*v.Alerts) == 0) && published.Alerts != nil && len(*published.Alerts) > 0 {
+		alerts := append([]dataset.Alert{}, *published.Alerts...)
+		v.Alerts = &alerts
+		inherited.Fields = append(inherited.Fields, InheritedAlerts)
+	}
+
+	return v, inherited
+}
+
+// inheritDimensionDescriptions returns a copy of the dimensions with empty descriptions filled in from the published
+// dimension with the same name, and whether any description was inherited
+func inheritDimensionDescriptions(dims, published []dataset.VersionDimension) ([]dataset.VersionDimension, bool) {
+	descriptions := map[string]string{}
+	for _, dim := range published {
+		descriptions[dim.Name] = dim.Description
+	}
+
+	inherited := false
+	updated := make([]dataset.VersionDimension, len(dims))
+	for i, dim := range dims {
+		if dim.Description == "" && descriptions[dim.Name] != "" {
+			dim.Description = descriptions[dim.Name]
+			inherited = true
+		}
+		updated[i] = dim
+	}
+	return updated, inherited
+}
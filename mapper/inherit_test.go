@@ -0,0 +1,78 @@
+package mapper
+
+import (
+	"testing"
+
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInheritMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("Given a published version with metadata", t, func() {
+		published := dataset.Version{
+			Edition:       "time-series",
+			Version:       3,
+			UsageNotes:    &[]dataset.UsageNote{{Title: "note", Note: "note text"}},
+			LatestChanges: []dataset.Change{{Name: "change", Description: "change description"}},
+			Alerts:        &[]dataset.Alert{{Description: "alert", Type: "correction"}},
+			Dimensions: []dataset.VersionDimension{
+				{Name: "geography", Description: "geography description"},
+				{Name: "time", Description: "time description"},
+			},
+		}
+
+		Convey("When the new version has no metadata and no fields are carried over", func() {
+			v := dataset.Version{
+				Version:    4,
+				Dimensions: []dataset.VersionDimension{{Name: "geography"}, {Name: "aggregate"}},
+			}
+			outcome, inherited := InheritMetadata(v, published, []string{})
+
+			Convey("Then usage notes and dimension descriptions are inherited", func() {
+				So(*outcome.UsageNotes, ShouldResemble, *published.UsageNotes)
+				So(outcome.Dimensions, ShouldResemble, []dataset.VersionDimension{
+					{Name: "geography", Description: "geography description"},
+					{Name: "aggregate"},
+				})
+				So(outcome.LatestChanges, ShouldBeEmpty)
+				So(outcome.Alerts, ShouldBeNil)
+				So(inherited.Edition, ShouldEqual, "time-series")
+				So(inherited.Version, ShouldEqual, 3)
+				So(inherited.Fields, ShouldResemble, []string{"usage_notes", "dimension_descriptions"})
+			})
+
+			Convey("And the original version is not modified", func() {
+				So(v.UsageNotes, ShouldBeNil)
+				So(v.Dimensions[0].Description, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When latest changes and alerts are carried over", func() {
+			outcome, inherited := InheritMetadata(dataset.Version{Version: 4}, published, []string{"latest_changes", "alerts"})
+
+			Convey("Then they are inherited", func() {
+				So(outcome.LatestChanges, ShouldResemble, published.LatestChanges)
+				So(*outcome.Alerts, ShouldResemble, *published.Alerts)
+				So(inherited.Fields, ShouldResemble, []string{"usage_notes", "latest_changes", "alerts"})
+			})
+		})
+
+		Convey("When the new version already has metadata", func() {
+			v := dataset.Version{
+				Version:       4,
+				UsageNotes:    &[]dataset.UsageNote{{Title: "new note"}},
+				LatestChanges: []dataset.Change{{Name: "new change"}},
+				Dimensions:    []dataset.VersionDimension{{Name: "time", Description: "new description"}},
+			}
+			outcome, inherited := InheritMetadata(v, published, []string{"latest_changes"})
+
+			Convey("Then it is not overwritten", func() {
+				So(outcome, ShouldResemble, v)
+				So(inherited.Fields, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,216 @@
+package mapper
+
+import (
+	"strings"
+	"time"
+
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/pkg/errors"
+)
+
+const noticeDateFormat = "02 Jan 2006"
+
+// LegacyMetadata applies the legacy metadata form to the current dataset and version, returning the updated
+// dataset and version to send to the dataset API. It is the inverse of EditDatasetVersionMetaData.
+// If the form has a contacts list it replaces all the dataset contacts. The flattened contact fields are then applied
+// to the first contact where they have been edited, and an error is returned if the list edits the same field differently
+func LegacyMetadata(m model.MetaData, d dataset.DatasetDetails, v dataset.Version) (dataset.DatasetDetails, dataset.Version, error) {
+	alerts, err := mapNotices(m.Notices, v.Alerts)
+	if err != nil {
+		return dataset.DatasetDetails{}, dataset.Version{}, errors.Wrap(err, "error whilst parsing notices")
+	}
+
+	d.Title = m.Title
+	d.Description = m.Summary
+	d.Keywords = mapKeywords(m.Keywords)
+	d.NationalStatistic = m.NationalStatistic
+	d.License = m.License
+	if m.Contacts != nil {
+		contacts, err := applyFlattenedContact(m.ContactName, m.ContactEmail, m.ContactTelephone, mapLegacyContacts(m.Contacts), d.Contacts)
+		if err != nil {
+			return dataset.DatasetDetails{}, dataset.Version{}, err
+		}
+		d.Contacts = contacts
+	} else {
+		d.Contacts = mapContact(m.ContactName, m.ContactEmail, m.ContactTelephone, d.Contacts)
+	}
+	d.RelatedDatasets = mapRelatedDatasets(m.RelatedDatasets)
+	d.Publications = mapPublications(m.RelatedPublications)
+	d.Methodologies = mapMethodologies(m.RelatedMethodologies)
+	d.ReleaseFrequency = m.ReleaseFrequency
+	d.NextRelease = m.NextReleaseDate
+	d.UnitOfMeasure = m.UnitOfMeassure
+	d.QMI.URL = m.QMI
+
+	v.ReleaseDate = m.ReleaseDate.ReleaseDate
+	v.Alerts = &alerts
+	v.Dimensions = m.Dimensions
+	v.UsageNotes = mapLegacyUsageNotes(m.UsageNotes)
+	v.LatestChanges = mapLegacyLatestChanges(m.LatestChanges, v.LatestChanges)
+
+	return d, v, nil
+}
+
+func mapKeywords(keywords string) *[]string {
+	mappedKeywords := []string{}
+	for _, keyword := range strings.Split(keywords, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			mappedKeywords = append(mappedKeywords, keyword)
+		}
+	}
+	return &mappedKeywords
+}
+
+// mapContact replaces the first contact, which is the only one shown on the legacy form, keeping any others
+func mapContact(name, email, telephone string, current *[]dataset.Contact) *[]dataset.Contact {
+	contact := dataset.Contact{Name: name, Email: email, Telephone: telephone}
+	if current == nil || len(*current) == 0 {
+		if contact == (dataset.Contact{}) {
+			return current
+		}
+		return &[]dataset.Contact{contact}
+	}
+
+	contacts := append([]dataset.Contact{contact}, (*current)[1:]...)
+	return &contacts
+}
+
+// applyFlattenedContact applies the flattened contact fields to the first contact of the contacts list. As the form
+// shows both, a flattened field is only taken as edited if it differs from the first of the current contacts
+func applyFlattenedContact(name, email, telephone string, contacts, current *[]dataset.Contact) (*[]dataset.Contact, error) {
+	var loaded, listed dataset.Contact
+	if current != nil && len(*current) > 0 {
+		loaded = (*current)[0]
+	}
+	if len(*contacts) > 0 {
+		listed = (*contacts)[0]
+	}
+
+	merged := listed
+	fields := []struct {
+		name                      string
+		flattened, loaded, listed string
+		merged                    *string
+	}{
+		{"contact_name", name, loaded.Name, listed.Name, &merged.Name},
+		{"contact_email", email, loaded.Email, listed.Email, &merged.Email},
+		{"contact_telephone", telephone, loaded.Telephone, listed.Telephone, &merged.Telephone},
+	}
+	for _, f := range fields {
+		if f.flattened == f.loaded {
+			continue
+		}
+		if f.listed != f.loaded && f.listed != f.flattened {
+			return nil, errors.Errorf("%s does not match the first contact in the contacts list", f.name)
+		}
+		*f.merged = f.flattened
+	}
+
+	if merged == listed {
+		return contacts, nil
+	}
+	return mapContact(merged.Name, merged.Email, merged.Telephone, contacts), nil
+}
+
+func mapLegacyContacts(c []model.Contact) *[]dataset.Contact {
+	contacts := []dataset.Contact{}
+	for _, contact := range c {
+		contacts = append(contacts, dataset.Contact{
+			Name:      contact.Name,
+			Email:     contact.Email,
+			Telephone: contact.Telephone,
+		})
+	}
+	return &contacts
+}
+
+func mapRelatedDatasets(rc []model.RelatedContent) *[]dataset.RelatedDataset {
+	relatedDatasets := []dataset.RelatedDataset{}
+	for _, content := range rc {
+		relatedDatasets = append(relatedDatasets, dataset.RelatedDataset{
+			Title: content.Title,
+			URL:   content.Href,
+		})
+	}
+	return &relatedDatasets
+}
+
+func mapPublications(rc []model.RelatedContent) *[]dataset.Publication {
+	publications := []dataset.Publication{}
+	for _, content := range rc {
+		publications = append(publications, dataset.Publication{
+			Title:       content.Title,
+			Description: content.Description,
+			URL:         content.Href,
+		})
+	}
+	return &publications
+}
+
+func mapMethodologies(rc []model.RelatedContent) *[]dataset.Methodology {
+	methodologies := []dataset.Methodology{}
+	for _, content := range rc {
+		methodologies = append(methodologies, dataset.Methodology{
+			Title:       content.Title,
+			Description: content.Description,
+			URL:         content.Href,
+		})
+	}
+	return &methodologies
+}
+
+// mapNotices maps notices back to alerts. Notice dates only hold the day, so the original alert date is kept
+// if the notice with the same ID still falls on that day
+func mapNotices(notices []model.Notice, current *[]dataset.Alert) ([]dataset.Alert, error) {
+	alerts := []dataset.Alert{}
+	for _, notice := range notices {
+		noticeDate, err := time.Parse(noticeDateFormat, notice.Date)
+		if err != nil {
+			return nil, errors.Wrap(err, "error whilst parsing time from notice date")
+		}
+
+		date := noticeDate.Format(time.RFC3339Nano)
+		if current != nil && notice.ID >= 0 && notice.ID < len(*current) {
+			currentDate, err := time.Parse(time.RFC3339Nano, (*current)[notice.ID].Date)
+			if err == nil && currentDate.Format(noticeDateFormat) == notice.Date {
+				date = (*current)[notice.ID].Date
+			}
+		}
+
+		alerts = append(alerts, dataset.Alert{
+			Date:        date,
+			Description: notice.Description,
+			Type:        notice.Type,
+		})
+	}
+	return alerts, nil
+}
+
+func mapLegacyUsageNotes(un []model.UsageNote) *[]dataset.UsageNote {
+	usageNotes := []dataset.UsageNote{}
+	for _, note := range un {
+		usageNotes = append(usageNotes, dataset.UsageNote{
+			Title: note.Title,
+			Note:  note.Note,
+		})
+	}
+	return &usageNotes
+}
+
+// mapLegacyLatestChanges maps latest changes back to changes. The change type isn't shown on the legacy form,
+// so it is kept from the change with the same ID
+func mapLegacyLatestChanges(lc []model.LatestChanges, current []dataset.Change) []dataset.Change {
+	changes := []dataset.Change{}
+	for _, change := range lc {
+		mappedChange := dataset.Change{
+			Name:        change.Title,
+			Description: change.Description,
+		}
+		if change.ID >= 0 && change.ID < len(current) {
+			mappedChange.Type = current[change.ID].Type
+		}
+		changes = append(changes, mappedChange)
+	}
+	return changes
+}
@@ -0,0 +1,157 @@
+package mapper
+
+import (
+	"testing"
+
+	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLegacyMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("Given a dataset and version", t, func() {
+		mockDataset := dataset.DatasetDetails{
+			ID:            "cpih01",
+			Title:         "title",
+			State:         "associated",
+			Contacts:      &[]dataset.Contact{{Name: "first"}, {Name: "second"}},
+			QMI:           dataset.Publication{Title: "qmi", URL: "http://qmi"},
+			Keywords:      &[]string{"one", "two"},
+			Methodologies: &[]dataset.Methodology{{Title: "methodology", Description: "description", URL: "http://methodology"}},
+			Publications:  &[]dataset.Publication{{Title: "publication", Description: "description", URL: "http://publication"}},
+			RelatedDatasets: &[]dataset.RelatedDataset{
+				{Title: "related", URL: "http://related"},
+			},
+			NationalStatistic: true,
+			ReleaseFrequency:  "monthly",
+		}
+		mockVersion := dataset.Version{
+			ID:            "version-id",
+			Edition:       "time-series",
+			Version:       2,
+			State:         "associated",
+			CollectionID:  "collection",
+			ReleaseDate:   "2021-01-01T00:00:00.000Z",
+			Alerts:        &[]dataset.Alert{{Date: "2021-01-12T09:30:00.000Z", Description: "alert", Type: "correction"}},
+			UsageNotes:    &[]dataset.UsageNote{{Title: "note", Note: "note text"}},
+			LatestChanges: []dataset.Change{{Name: "change", Description: "change description", Type: "summary"}},
+			Dimensions:    []dataset.VersionDimension{{Name: "geography", Description: "description"}},
+		}
+
+		Convey("When the legacy metadata is mapped and mapped back", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			d, v, err := LegacyMetadata(legacy.MetaData, mockDataset, mockVersion)
+
+			Convey("Then the dataset and version are unchanged", func() {
+				So(err, ShouldBeNil)
+				So(d, ShouldResemble, mockDataset)
+				So(v, ShouldResemble, mockVersion)
+			})
+		})
+
+		Convey("When the legacy metadata form has been edited", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			form := legacy.MetaData
+			form.Title = "new title"
+			form.Keywords = "three, , four"
+			form.Contacts[0].Name = "new contact"
+			form.Contacts = append(form.Contacts, model.Contact{Name: "third", Email: "third@ons.gov.uk"})
+			form.QMI = "http://new-qmi"
+			form.Notices = append(form.Notices, model.Notice{ID: 1, Type: "alert", Date: "02 Feb 2021", Description: "new alert"})
+			form.UsageNotes = nil
+			form.LatestChanges[0].Title = "new change"
+			form.RelatedDatasets = nil
+
+			d, v, err := LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then the edited fields are updated", func() {
+				So(err, ShouldBeNil)
+				So(d.Title, ShouldEqual, "new title")
+				So(*d.Keywords, ShouldResemble, []string{"three", "four"})
+				So(*d.Contacts, ShouldResemble, []dataset.Contact{{Name: "new contact"}, {Name: "second"}, {Name: "third", Email: "third@ons.gov.uk"}})
+				So(d.QMI, ShouldResemble, dataset.Publication{Title: "qmi", URL: "http://new-qmi"})
+				So(*d.RelatedDatasets, ShouldBeEmpty)
+				So(d.State, ShouldEqual, "associated")
+				So(*v.Alerts, ShouldResemble, []dataset.Alert{
+					{Date: "2021-01-12T09:30:00.000Z", Description: "alert", Type: "correction"},
+					{Date: "2021-02-02T00:00:00Z", Description: "new alert", Type: "alert"},
+				})
+				So(*v.UsageNotes, ShouldBeEmpty)
+				So(v.LatestChanges, ShouldResemble, []dataset.Change{{Name: "new change", Description: "change description", Type: "summary"}})
+			})
+		})
+
+		Convey("When the legacy metadata form has no contacts list", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			form := legacy.MetaData
+			form.Contacts = nil
+			form.ContactName = "new contact"
+
+			d, _, err := LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then the flattened contact fields replace the first contact", func() {
+				So(err, ShouldBeNil)
+				So(*d.Contacts, ShouldResemble, []dataset.Contact{{Name: "new contact"}, {Name: "second"}})
+			})
+		})
+
+		Convey("When only the flattened contact name has been edited on a form with a contacts list", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			form := legacy.MetaData
+			So(form.Contacts, ShouldNotBeNil)
+			form.ContactName = "new contact"
+
+			d, _, err := LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then the edit is applied to the first contact", func() {
+				So(err, ShouldBeNil)
+				So(*d.Contacts, ShouldResemble, []dataset.Contact{{Name: "new contact"}, {Name: "second"}})
+			})
+		})
+
+		Convey("When only the first contact in the contacts list has been edited", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			form := legacy.MetaData
+			form.Contacts = []model.Contact{{Name: "listed contact"}, {Name: "second"}}
+
+			d, _, err := LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then the list edit is kept", func() {
+				So(err, ShouldBeNil)
+				So(*d.Contacts, ShouldResemble, []dataset.Contact{{Name: "listed contact"}, {Name: "second"}})
+			})
+		})
+
+		Convey("When the flattened contact and the contacts list edit the same field differently", func() {
+			legacy, err := EditDatasetVersionMetaData(mockDataset, mockVersion)
+			So(err, ShouldBeNil)
+			form := legacy.MetaData
+			form.ContactName = "new contact"
+			form.Contacts = []model.Contact{{Name: "listed contact"}, {Name: "second"}}
+
+			_, _, err = LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "contact_name does not match the first contact in the contacts list")
+			})
+		})
+
+		Convey("When a notice date is invalid", func() {
+			form := model.MetaData{Notices: []model.Notice{{Date: "not a date"}}}
+			_, _, err := LegacyMetadata(form, mockDataset, mockVersion)
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
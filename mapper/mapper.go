@@ -16,6 +16,20 @@ import (
 	"github.com/pkg/errors"
 )
 
+// releaseDateFormat is the format of the release dates shown in the versions list
+const releaseDateFormat = "02 January 2006"
+
+// VersionStates are the version states a versions list can be filtered by
+var VersionStates = map[string]bool{"created": true, "edition-confirmed": true, "associated": true, "published": true}
+
+// Fields and orders a versions list can be sorted by
+const (
+	SortByVersion     = "version"
+	SortByReleaseDate = "release_date"
+	OrderAsc          = "asc"
+	OrderDesc         = "desc"
+)
+
 type related struct {
 	publications  []model.RelatedContent
 	methodologies []model.RelatedContent
@@ -31,6 +45,7 @@ func AllDatasets(datasets dataset.List) []model.Dataset {
 		mappedDatasets = append(mappedDatasets, model.Dataset{
 			ID:    ds.ID,
 			Title: ds.Next.Title,
+			Type:  ds.Next.Type,
 		})
 	}
 
@@ -41,6 +56,54 @@ func AllDatasets(datasets dataset.List) []model.Dataset {
 	return mappedDatasets
 }
 
+// FilterDatasets returns the datasets whose title or ID contains the query (case insensitive)
+// and, if a dataset type is given, whose type matches it
+func FilterDatasets(datasets []model.Dataset, query, datasetType string) []model.Dataset {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" && datasetType == "" {
+		return datasets
+	}
+
+	var filtered []model.Dataset
+	for _, ds := range datasets {
+		if datasetType != "" && !strings.EqualFold(ds.Type, datasetType) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(ds.Title), query) && !strings.Contains(strings.ToLower(ds.ID), query) {
+			continue
+		}
+		filtered = append(filtered, ds)
+	}
+	return filtered
+}
+
+// DatasetsPage returns the page of datasets starting at offset and holding at most limit items.
+// A limit of 0 returns every dataset from the offset onwards
+func DatasetsPage(datasets []model.Dataset, offset, limit int) model.DatasetsPage {
+	totalCount := len(datasets)
+	if offset > totalCount {
+		offset = totalCount
+	}
+	if limit == 0 {
+		limit = totalCount
+	}
+	end := offset + limit
+	if end > totalCount {
+		end = totalCount
+	}
+
+	items := make([]model.Dataset, 0, end-offset)
+	items = append(items, datasets[offset:end]...)
+
+	return model.DatasetsPage{
+		Items:      items,
+		Count:      len(items),
+		Offset:     offset,
+		Limit:      limit,
+		TotalCount: totalCount,
+	}
+}
+
 func AllVersions(ctx context.Context, dataset dataset.Dataset, edition dataset.Edition, versions dataset.VersionsList) model.VersionsPage {
 	datasetName := dataset.Next.Title
 	editionName := edition.Edition
@@ -55,7 +118,7 @@ func AllVersions(ctx context.Context, dataset dataset.Dataset, edition dataset.E
 		if err != nil {
 			log.Warn(ctx, "failed to parse release date", log.FormatErrors([]error{err}))
 		} else {
-			timeF = time.Format("02 January 2006")
+			timeF = time.Format(releaseDateFormat)
 		}
 		mappedVersions = append(mappedVersions, model.Version{
 			ID:          v.ID,
@@ -77,6 +140,81 @@ func AllVersions(ctx context.Context, dataset dataset.Dataset, edition dataset.E
 	}
 }
 
+// VersionStateCounts returns the number of versions in each state. Every filterable state is included, even if it has no versions
+func VersionStateCounts(versions dataset.VersionsList) map[string]int {
+	counts := map[string]int{}
+	for state := range VersionStates {
+		counts[state] = 0
+	}
+	for _, v := range versions.Items {
+		counts[v.State]++
+	}
+	return counts
+}
+
+// FilterVersions returns the versions in one of the given states. All versions are returned if no states are given
+func FilterVersions(versions dataset.VersionsList, states []string) dataset.VersionsList {
+	if len(states) == 0 {
+		return versions
+	}
+
+	filtered := dataset.VersionsList{Items: []dataset.Version{}}
+	for _, v := range versions.Items {
+		for _, state := range states {
+			if v.State == state {
+				filtered.Items = append(filtered.Items, v)
+				break
+			}
+		}
+	}
+	filtered.Count = len(filtered.Items)
+	filtered.TotalCount = len(filtered.Items)
+	return filtered
+}
+
+// SortVersions sorts the versions by version number or release date in the given order. Release dates are compared using the
+// dataset API values in raw, as the mapped dates are formatted for display. Versions without a release date are sorted last,
+// and versions with the same release date by version number
+func SortVersions(versions []model.Version, raw []dataset.Version, sortBy, order string) {
+	less := func(a, b model.Version) bool {
+		if order == OrderAsc {
+			return a.Version < b.Version
+		}
+		return a.Version > b.Version
+	}
+
+	if sortBy == SortByReleaseDate {
+		releaseDates := map[string]string{}
+		for _, v := range raw {
+			releaseDates[v.ID] = v.ReleaseDate
+		}
+
+		byVersion := less
+		less = func(a, b model.Version) bool {
+			aDate, aErr := time.Parse(time.RFC3339, releaseDates[a.ID])
+			bDate, bErr := time.Parse(time.RFC3339, releaseDates[b.ID])
+			switch {
+			case aErr != nil && bErr != nil:
+				return byVersion(a, b)
+			case aErr != nil:
+				return false
+			case bErr != nil:
+				return true
+			case aDate.Equal(bDate):
+				return byVersion(a, b)
+			case order == OrderAsc:
+				return aDate.Before(bDate)
+			default:
+				return aDate.After(bDate)
+			}
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return less(versions[i], versions[j])
+	})
+}
+
 func EditMetadata(d *dataset.DatasetDetails, v dataset.Version, dim []dataset.VersionDimension, c zebedee.Collection) model.EditMetadata {
 	mappedMetadata := model.EditMetadata{
 		Dataset:      *d,
@@ -162,7 +300,7 @@ func EditDatasetVersionMetaData(d dataset.DatasetDetails, v dataset.Version) (mo
 			Email:     "",
 		},
 	}
-	if d.Contacts != nil {
+	if d.Contacts != nil && len(*d.Contacts) > 0 {
 		contacts = *d.Contacts
 	}
 
@@ -193,6 +331,7 @@ func EditDatasetVersionMetaData(d dataset.DatasetDetails, v dataset.Version) (mo
 		ContactName:          contacts[0].Name,
 		ContactEmail:         contacts[0].Email,
 		ContactTelephone:     contacts[0].Telephone,
+		Contacts:             mapContacts(d.Contacts),
 		RelatedDatasets:      relatedContent.datasets,
 		RelatedPublications:  relatedContent.publications,
 		RelatedMethodologies: relatedContent.methodologies,
@@ -304,6 +443,25 @@ func mapUsageNotes(un *[]dataset.UsageNote) []model.UsageNote {
 	return usageNotes
 }
 
+func mapContacts(c *[]dataset.Contact) []model.Contact {
+	var contacts []model.Contact
+	if c == nil {
+		return contacts
+	}
+
+	for i, contact := range *c {
+		contacts = append(contacts, model.Contact{
+			ID:                    i,
+			Name:                  contact.Name,
+			Email:                 contact.Email,
+			Telephone:             contact.Telephone,
+			SimpleListHeading:     contact.Name,
+			SimpleListDescription: contact.Email,
+		})
+	}
+	return contacts
+}
+
 func mapLatestChanges(un []dataset.Change) []model.LatestChanges {
 	var latestChanges []model.LatestChanges
 
@@ -319,15 +477,16 @@ func mapLatestChanges(un []dataset.Change) []model.LatestChanges {
 	return latestChanges
 }
 
-// Topics takes babbage topics respond and returns a slice with topic titles
-func Topics(tpcs babbageclient.TopicsResult) []model.Topics {
-	var topics []model.Topics
-	if len(tpcs.Topics.Results) > 0 {
-		for _, tpc := range tpcs.Topics.Results {
-			topics = append(topics, model.Topics{
-				Title: tpc.Description.Title,
-			})
-		}
+// TopicTree maps babbage's taxonomy to the tree of topics and their subtopics
+func TopicTree(nodes []babbageclient.TaxonomyNode) []model.Topic {
+	topics := []model.Topic{}
+	for _, node := range nodes {
+		topics = append(topics, model.Topic{
+			ID:        node.ID,
+			Title:     node.Title,
+			URI:       node.URI,
+			Subtopics: TopicTree(node.Children),
+		})
 	}
 	return topics
 }
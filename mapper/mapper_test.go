@@ -141,41 +141,77 @@ func TestUnitMapper(t *testing.T) {
 		So(len(mapped), ShouldEqual, 4)
 	})
 
-	mockTopics := babbage.TopicsResult{
-		Topics: babbage.Topic{
-			Results: []babbage.Result{{
-				Description: babbage.Description{
-					Title: "test 1",
-				},
-				URI:  "/test/uri/1",
-				Type: "page",
-			},
-				{
-					Description: babbage.Description{
-						Title: "test 2",
-					},
-					URI:  "/test/uri/2",
-					Type: "page",
-				}},
-		},
+	mockDatasets := []model.Dataset{
+		{ID: "cpih01", Title: "Consumer Prices Index", Type: "filterable"},
+		{ID: "TS009", Title: "Sex", Type: "cantabular_flexible_table"},
+		{ID: "wellbeing", Title: "Personal well-being estimates", Type: "filterable"},
 	}
 
-	mockEmptyTopics := babbage.TopicsResult{
-		Topics: babbage.Topic{
-			Results: []babbage.Result{},
+	Convey("test FilterDatasets", t, func() {
+		Convey("returns all datasets when no filters are given", func() {
+			So(FilterDatasets(mockDatasets, "", ""), ShouldResemble, mockDatasets)
+		})
+		Convey("matches the query against title and ID regardless of case", func() {
+			So(FilterDatasets(mockDatasets, "prices", ""), ShouldResemble, []model.Dataset{mockDatasets[0]})
+			So(FilterDatasets(mockDatasets, "ts00", ""), ShouldResemble, []model.Dataset{mockDatasets[1]})
+		})
+		Convey("matches the dataset type", func() {
+			So(FilterDatasets(mockDatasets, "", "filterable"), ShouldResemble, []model.Dataset{mockDatasets[0], mockDatasets[2]})
+			So(FilterDatasets(mockDatasets, "well", "filterable"), ShouldResemble, []model.Dataset{mockDatasets[2]})
+		})
+		Convey("returns nil when nothing matches", func() {
+			So(FilterDatasets(mockDatasets, "nothing", ""), ShouldBeNil)
+		})
+	})
+
+	Convey("test DatasetsPage", t, func() {
+		Convey("returns every dataset when limit is 0", func() {
+			page := DatasetsPage(mockDatasets, 0, 0)
+			So(page, ShouldResemble, model.DatasetsPage{Items: mockDatasets, Count: 3, Offset: 0, Limit: 3, TotalCount: 3})
+		})
+		Convey("returns the requested page", func() {
+			page := DatasetsPage(mockDatasets, 1, 1)
+			So(page, ShouldResemble, model.DatasetsPage{Items: []model.Dataset{mockDatasets[1]}, Count: 1, Offset: 1, Limit: 1, TotalCount: 3})
+		})
+		Convey("truncates the last page", func() {
+			page := DatasetsPage(mockDatasets, 2, 5)
+			So(page, ShouldResemble, model.DatasetsPage{Items: []model.Dataset{mockDatasets[2]}, Count: 1, Offset: 2, Limit: 5, TotalCount: 3})
+		})
+		Convey("returns an empty page when the offset is out of range", func() {
+			page := DatasetsPage(mockDatasets, 10, 5)
+			So(page, ShouldResemble, model.DatasetsPage{Items: []model.Dataset{}, Count: 0, Offset: 3, Limit: 5, TotalCount: 3})
+		})
+	})
+
+	mockTaxonomy := []babbage.TaxonomyNode{
+		{
+			ID:    "economy",
+			Title: "Economy",
+			URI:   "/economy",
+			Type:  "taxonomy_landing_page",
+			Children: []babbage.TaxonomyNode{
+				{ID: "inflationandpriceindices", Title: "Inflation and price indices", URI: "/economy/inflationandpriceindices", Type: "product_page", Children: []babbage.TaxonomyNode{}},
+			},
 		},
 	}
 
-	expectedTopics := []model.Topics{{Title: "test 1"}, {Title: "test 2"}}
-
-	Convey("test Topics", t, func() {
-		Convey("maps correctly if results have topics", func() {
-			outcome := Topics(mockTopics)
-			So(outcome, ShouldResemble, expectedTopics)
+	Convey("test TopicTree", t, func() {
+		Convey("maps topics and their subtopics", func() {
+			outcome := TopicTree(mockTaxonomy)
+			So(outcome, ShouldResemble, []model.Topic{
+				{
+					ID:    "economy",
+					Title: "Economy",
+					URI:   "/economy",
+					Subtopics: []model.Topic{
+						{ID: "inflationandpriceindices", Title: "Inflation and price indices", URI: "/economy/inflationandpriceindices", Subtopics: []model.Topic{}},
+					},
+				},
+			})
 		})
-		Convey("retruns empty slice and doesn't error if no results", func() {
-			outcome := Topics(mockEmptyTopics)
-			So(outcome, ShouldResemble, []model.Topics(nil))
+		Convey("returns an empty slice if there are no topics", func() {
+			outcome := TopicTree(nil)
+			So(outcome, ShouldResemble, []model.Topic{})
 		})
 	})
 
@@ -219,6 +255,75 @@ func TestUnitMapper(t *testing.T) {
 			So(mapped, ShouldResemble, expectedVersionsPage)
 		})
 	})
+
+	Convey("test VersionStateCounts", t, func() {
+		Convey("counts versions in each state", func() {
+			So(VersionStateCounts(mockedAllVersions), ShouldResemble, map[string]int{
+				"created":           0,
+				"edition-confirmed": 1,
+				"associated":        0,
+				"published":         2,
+			})
+		})
+	})
+
+	Convey("test FilterVersions", t, func() {
+		Convey("returns all versions if no states are given", func() {
+			So(FilterVersions(mockedAllVersions, []string{}), ShouldResemble, mockedAllVersions)
+		})
+
+		Convey("returns the versions in the given states", func() {
+			filtered := FilterVersions(mockedAllVersions, []string{"edition-confirmed", "associated"})
+			So(len(filtered.Items), ShouldEqual, 1)
+			So(filtered.Items[0].ID, ShouldEqual, "test-id-3")
+			So(filtered.TotalCount, ShouldEqual, 1)
+		})
+
+		Convey("returns an empty list if no versions are in the given states", func() {
+			So(FilterVersions(mockedAllVersions, []string{"created"}).Items, ShouldBeEmpty)
+		})
+	})
+
+	Convey("test SortVersions", t, func() {
+		versionIDs := func(versions []model.Version) []string {
+			var ids []string
+			for _, v := range versions {
+				ids = append(ids, v.ID)
+			}
+			return ids
+		}
+
+		Convey("sorts by version number ascending", func() {
+			versions := append([]model.Version{}, expectedAllVersions...)
+			SortVersions(versions, mockedAllVersions.Items, "version", "asc")
+			So(versionIDs(versions), ShouldResemble, []string{"test-id-1", "test-id-2", "test-id-3"})
+		})
+
+		Convey("sorts by release date descending, with undated versions last", func() {
+			versions := append([]model.Version{}, expectedAllVersions...)
+			SortVersions(versions, mockedAllVersions.Items, "release_date", "desc")
+			So(versionIDs(versions), ShouldResemble, []string{"test-id-2", "test-id-1", "test-id-3"})
+		})
+
+		Convey("sorts by release date ascending, with undated versions last", func() {
+			versions := append([]model.Version{}, expectedAllVersions...)
+			SortVersions(versions, mockedAllVersions.Items, "release_date", "asc")
+			So(versionIDs(versions), ShouldResemble, []string{"test-id-1", "test-id-2", "test-id-3"})
+		})
+
+		Convey("sorts by the time of the release, not only the displayed day", func() {
+			raw := []dataset.Version{
+				{ID: "morning", Version: 2, ReleaseDate: "2020-11-07T09:30:00.000Z"},
+				{ID: "afternoon", Version: 1, ReleaseDate: "2020-11-07T15:00:00.000Z"},
+			}
+			versions := []model.Version{
+				{ID: "morning", Version: 2, ReleaseDate: "07 November 2020"},
+				{ID: "afternoon", Version: 1, ReleaseDate: "07 November 2020"},
+			}
+			SortVersions(versions, raw, "release_date", "desc")
+			So(versionIDs(versions), ShouldResemble, []string{"afternoon", "morning"})
+		})
+	})
 }
 
 func TestMetadata(t *testing.T) {
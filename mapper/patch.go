@@ -0,0 +1,114 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+)
+
+// EditableMetadataFields are the editable metadata fields that can be patched. Dimensions are left out as they are
+// specific to each version and are edited through the instance
+var EditableMetadataFields = map[string]bool{
+	"alerts":             true,
+	"canonical_topic":    true,
+	"contacts":           true,
+	"description":        true,
+	"keywords":           true,
+	"latest_changes":     true,
+	"license":            true,
+	"methodologies":      true,
+	"national_statistic": true,
+	"next_release":       true,
+	"publications":       true,
+	"qmi":                true,
+	"related_content":    true,
+	"related_datasets":   true,
+	"release_date":       true,
+	"release_frequency":  true,
+	"subtopics":          true,
+	"survey":             true,
+	"title":              true,
+	"unit_of_measure":    true,
+	"usage_notes":        true,
+}
+
+// PatchEditableMetadata replaces the value of each patched field, keyed by its JSON name. A null value clears the field
+func PatchEditableMetadata(m dataset.EditableMetadata, patch map[string]json.RawMessage) (dataset.EditableMetadata, error) {
+	fields := jsonFields(m)
+	for name, value := range patch {
+		if !EditableMetadataFields[name] {
+			return m, fmt.Errorf("%s is not an editable metadata field", name)
+		}
+		if string(value) == "null" {
+			delete(fields, name)
+			continue
+		}
+		fields[name] = value
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return m, err
+	}
+
+	var patched dataset.EditableMetadata
+	if err = json.Unmarshal(b, &patched); err != nil {
+		return m, err
+	}
+	return patched, nil
+}
+
+// MergePatchEditableMetadata applies an RFC 7396 JSON merge patch to the metadata. Objects in the patch are merged into the
+// current value, null values remove the field and any other value replaces it
+func MergePatchEditableMetadata(m dataset.EditableMetadata, patch map[string]json.RawMessage) (dataset.EditableMetadata, error) {
+	for name := range patch {
+		if !EditableMetadataFields[name] {
+			return m, fmt.Errorf("%s is not an editable metadata field", name)
+		}
+	}
+
+	var target interface{}
+	if err := remarshal(m, &target); err != nil {
+		return m, err
+	}
+	var p interface{}
+	if err := remarshal(patch, &p); err != nil {
+		return m, err
+	}
+
+	var merged dataset.EditableMetadata
+	if err := remarshal(mergePatch(target, p), &merged); err != nil {
+		return m, err
+	}
+	return merged, nil
+}
+
+// mergePatch implements the MergePatch function from RFC 7396 on decoded JSON values
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+func remarshal(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, to)
+}
@@ -0,0 +1,111 @@
+package mapper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPatchEditableMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("test PatchEditableMetadata", t, func() {
+		m := dataset.EditableMetadata{
+			Title:            "title",
+			License:          "license",
+			ReleaseFrequency: "monthly",
+			Contacts:         []dataset.Contact{{Name: "old contact"}},
+		}
+
+		Convey("replaces the patched fields and keeps the others", func() {
+			patched, err := PatchEditableMetadata(m, map[string]json.RawMessage{
+				"release_frequency": json.RawMessage(`"quarterly"`),
+				"contacts":          json.RawMessage(`[{"name":"new contact","email":"new@ons.gov.uk"}]`),
+			})
+
+			So(err, ShouldBeNil)
+			So(patched, ShouldResemble, dataset.EditableMetadata{
+				Title:            "title",
+				License:          "license",
+				ReleaseFrequency: "quarterly",
+				Contacts:         []dataset.Contact{{Name: "new contact", Email: "new@ons.gov.uk"}},
+			})
+		})
+
+		Convey("clears a field patched with null", func() {
+			patched, err := PatchEditableMetadata(m, map[string]json.RawMessage{"license": json.RawMessage("null")})
+
+			So(err, ShouldBeNil)
+			So(patched.License, ShouldBeEmpty)
+			So(patched.Title, ShouldEqual, "title")
+		})
+
+		Convey("returns an error for a field that cannot be patched", func() {
+			_, err := PatchEditableMetadata(m, map[string]json.RawMessage{"dimensions": json.RawMessage("[]")})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "dimensions is not an editable metadata field")
+		})
+
+		Convey("returns an error for a value of the wrong type", func() {
+			_, err := PatchEditableMetadata(m, map[string]json.RawMessage{"keywords": json.RawMessage(`"one"`)})
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitMergePatchEditableMetadata(t *testing.T) {
+	t.Parallel()
+
+	Convey("test MergePatchEditableMetadata", t, func() {
+		m := dataset.EditableMetadata{
+			Title:       "title",
+			Description: "descripton with a typo",
+			License:     "license",
+			QMI:         &dataset.Publication{Title: "qmi", URL: "https://www.ons.gov.uk/qmi", Description: "qmi description"},
+			Keywords:    []string{"one", "two"},
+		}
+
+		Convey("changes only the fields in the patch", func() {
+			merged, err := MergePatchEditableMetadata(m, map[string]json.RawMessage{
+				"description": json.RawMessage(`"description"`),
+			})
+
+			So(err, ShouldBeNil)
+			expected := m
+			expected.Description = "description"
+			So(merged, ShouldResemble, expected)
+		})
+
+		Convey("merges objects, removes null members and replaces arrays", func() {
+			merged, err := MergePatchEditableMetadata(m, map[string]json.RawMessage{
+				"qmi":      json.RawMessage(`{"title":"new qmi","description":null}`),
+				"keywords": json.RawMessage(`["three"]`),
+				"license":  json.RawMessage(`null`),
+			})
+
+			So(err, ShouldBeNil)
+			So(merged.QMI, ShouldResemble, &dataset.Publication{Title: "new qmi", URL: "https://www.ons.gov.uk/qmi"})
+			So(merged.Keywords, ShouldResemble, []string{"three"})
+			So(merged.License, ShouldBeEmpty)
+			So(merged.Title, ShouldEqual, "title")
+		})
+
+		Convey("returns an error for a field that cannot be patched", func() {
+			_, err := MergePatchEditableMetadata(m, map[string]json.RawMessage{"dimensions": json.RawMessage(`[]`)})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "dimensions is not an editable metadata field")
+		})
+
+		Convey("returns an error for a value of the wrong type", func() {
+			_, err := MergePatchEditableMetadata(m, map[string]json.RawMessage{"title": json.RawMessage(`{"text":"title"}`)})
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+
 	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	datasetclient "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 )
@@ -8,6 +10,15 @@ import (
 type Dataset struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
+	Type  string `json:"type,omitempty"`
+}
+
+type DatasetsPage struct {
+	Items      []Dataset `json:"items"`
+	Count      int       `json:"count"`
+	Offset     int       `json:"offset"`
+	Limit      int       `json:"limit"`
+	TotalCount int       `json:"total_count"`
 }
 
 type EditionsPage struct {
@@ -19,12 +30,57 @@ type Edition struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
 	ReleaseDate string `json:"release_date"`
+	LookupError string `json:"lookup_error,omitempty"`
+}
+
+// CollectionOverview lists the datasets and dataset versions in a collection
+type CollectionOverview struct {
+	ID              string                     `json:"id"`
+	Name            string                     `json:"name"`
+	ApprovalStatus  string                     `json:"approval_status"`
+	Datasets        []CollectionDataset        `json:"datasets"`
+	DatasetVersions []CollectionDatasetVersion `json:"dataset_versions"`
+}
+
+type CollectionDataset struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	LastEditedBy string `json:"last_edited_by"`
+	LookupError  string `json:"lookup_error,omitempty"`
+}
+
+type CollectionDatasetVersion struct {
+	DatasetID    string `json:"dataset_id"`
+	DatasetTitle string `json:"dataset_title"`
+	Edition      string `json:"edition"`
+	Version      string `json:"version"`
+	State        string `json:"state"`
+	LastEditedBy string `json:"last_edited_by"`
+	LookupError  string `json:"lookup_error,omitempty"`
+}
+
+type EditionDetail struct {
+	ID                       string              `json:"id"`
+	DatasetID                string              `json:"dataset_id"`
+	DatasetName              string              `json:"dataset_name"`
+	State                    string              `json:"state"`
+	Links                    datasetclient.Links `json:"links"`
+	VersionCount             int                 `json:"version_count"`
+	LatestPublishedVersion   *Version            `json:"latest_published_version,omitempty"`
+	LatestUnpublishedVersion *Version            `json:"latest_unpublished_version,omitempty"`
+}
+
+type CreateEdition struct {
+	Edition    string `json:"edition"`
+	InstanceID string `json:"instance_id"`
 }
 
 type VersionsPage struct {
-	DatasetName string    `json:"dataset_name"`
-	EditionName string    `json:"edition_name"`
-	Versions    []Version `json:"versions"`
+	DatasetName string         `json:"dataset_name"`
+	EditionName string         `json:"edition_name"`
+	Versions    []Version      `json:"versions"`
+	StateCounts map[string]int `json:"state_counts,omitempty"`
 }
 type Version struct {
 	ID          string `json:"id"`
@@ -42,13 +98,134 @@ type EditMetadata struct {
 	CollectionState        string                           `json:"collection_state"`
 	CollectionLastEditedBy string                           `json:"collection_last_edited_by"`
 	VersionEtag            string                           `json:"version_etag"`
+	InstanceEtag           string                           `json:"instance_etag"`
+	Inherited              *InheritedMetadata               `json:"inherited,omitempty"`
+	Original               *EditMetadata                    `json:"original,omitempty"`
+}
+
+type InheritedMetadata struct {
+	Edition string   `json:"edition"`
+	Version int      `json:"version"`
+	Fields  []string `json:"fields"`
+}
+
+type EditDimension struct {
+	Label        string `json:"label"`
+	Description  string `json:"description"`
+	InstanceEtag string `json:"instance_etag,omitempty"`
+}
+
+type CreateDataset struct {
+	Title          string                  `json:"title"`
+	Description    string                  `json:"description"`
+	CanonicalTopic string                  `json:"canonical_topic"`
+	Subtopics      []string                `json:"subtopics"`
+	Contacts       []datasetclient.Contact `json:"contacts"`
+	Type           string                  `json:"type"`
+}
+
+type ErrorResponse struct {
+	Code            string `json:"code"`
+	Message         string `json:"message"`
+	UpstreamService string `json:"upstream_service,omitempty"`
+	UpstreamStatus  int    `json:"upstream_status,omitempty"`
+	RequestID       string `json:"request_id,omitempty"`
+}
+
+type ValidationError struct {
+	ErrorResponse
+	Errors []FieldError `json:"errors"`
+}
+
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+type UpdateError struct {
+	ErrorResponse
+	Succeeded        []string `json:"succeeded"`
+	Failed           string   `json:"failed"`
+	RolledBack       []string `json:"rolled_back"`
+	PartlyRolledBack []string `json:"partly_rolled_back"`
+	NotRestored      []string `json:"not_restored"`
+	RollbackFailed   []string `json:"rollback_failed"`
+}
+
+// MetadataConflict is returned when metadata has been changed since the client loaded it. Conflicts is null if the
+// metadata the client loaded is not known, in which case the current metadata is returned instead
+type MetadataConflict struct {
+	ErrorResponse
+	CurrentEtag string                          `json:"current_etag"`
+	Conflicts   []FieldConflict                 `json:"conflicts"`
+	Current     *datasetclient.EditableMetadata `json:"current,omitempty"`
+}
+
+type FieldConflict struct {
+	Field     string          `json:"field"`
+	Submitted json.RawMessage `json:"submitted"`
+	Current   json.RawMessage `json:"current"`
+}
+
+type MetadataDryRun struct {
+	DryRun      bool          `json:"dry_run"`
+	VersionEtag string        `json:"version_etag"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field   string          `json:"field"`
+	Current json.RawMessage `json:"current"`
+	Planned json.RawMessage `json:"planned"`
+}
+
+type BulkMetadata struct {
+	Patch   map[string]json.RawMessage `json:"patch"`
+	Targets []BulkMetadataTarget       `json:"targets"`
+}
+
+type BulkMetadataTarget struct {
+	DatasetID string `json:"dataset_id"`
+	Edition   string `json:"edition"`
+	Version   string `json:"version"`
+}
+
+type BulkMetadataReport struct {
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkMetadataResult `json:"results"`
+}
+
+type BulkMetadataResult struct {
+	BulkMetadataTarget
+	Status  int            `json:"status"`
+	Changes []FieldChange  `json:"changes"`
+	Error   *ErrorResponse `json:"error,omitempty"`
+}
+
+type MetadataDiff struct {
+	DatasetID        string      `json:"dataset_id"`
+	Edition          string      `json:"edition"`
+	Version          int         `json:"version"`
+	PublishedEdition string      `json:"published_edition,omitempty"`
+	PublishedVersion int         `json:"published_version,omitempty"`
+	DatasetChanges   []FieldDiff `json:"dataset_changes"`
+	VersionChanges   []FieldDiff `json:"version_changes"`
+}
+
+type FieldDiff struct {
+	Field     string          `json:"field"`
+	Published json.RawMessage `json:"published"`
+	Draft     json.RawMessage `json:"draft"`
 }
 
 type EditVersionMetaData struct {
-	MetaData   MetaData `json:"meta_data"`
-	Collection string   `json:"collection"`
-	InstanceID string   `json:"instance_id"`
-	Published  bool     `json:"published"`
+	MetaData        MetaData `json:"meta_data"`
+	Collection      string   `json:"collection"`
+	CollectionState string   `json:"collection_state,omitempty"`
+	InstanceID      string   `json:"instance_id"`
+	Published       bool     `json:"published"`
+	VersionEtag     string   `json:"version_etag"`
 }
 
 type MetaData struct {
@@ -68,6 +245,7 @@ type MetaData struct {
 	ContactName          string           `json:"contact_name"`
 	ContactEmail         string           `json:"contact_email"`
 	ContactTelephone     string           `json:"contact_telephone"`
+	Contacts             []Contact        `json:"contacts"`
 	RelatedDatasets      []RelatedContent `json:"related_datasets"`
 	RelatedPublications  []RelatedContent `json:"related_publications"`
 	RelatedMethodologies []RelatedContent `json:"related_methodologies"`
@@ -107,6 +285,15 @@ type LatestChanges struct {
 	SimpleListDescription string `json:"simple_list_description"`
 }
 
+type Contact struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	Email                 string `json:"email"`
+	Telephone             string `json:"telephone"`
+	SimpleListHeading     string `json:"simple_list_heading"`
+	SimpleListDescription string `json:"simple_list_description"`
+}
+
 type RelatedContent struct {
 	ID                    int    `json:"id"`
 	Title                 string `json:"title"`
@@ -124,6 +311,10 @@ func (d Dataset) GetLabel() string {
 	return d.Title
 }
 
-type Topics struct {
-	Title string `json:"title"`
+// Topic is a topic in the taxonomy. Its ID is used as a dataset's canonical topic or one of its subtopics
+type Topic struct {
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	URI       string  `json:"uri"`
+	Subtopics []Topic `json:"subtopics"`
 }
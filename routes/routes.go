@@ -3,9 +3,10 @@ package routes
 import (
 	"net/http"
 
-	ds "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	zc "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/cache"
+	ds "github.com/ONSdigital/dp-publishing-dataset-controller/clients/dataset"
 	bc "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/config"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/dataset"
@@ -16,11 +17,24 @@ import (
 func Init(router *mux.Router, cfg *config.Config, hc healthcheck.HealthCheck, dc *ds.Client, zc *zc.Client, bc *bc.Client) {
 	router.StrictSlash(true).Path("/health").HandlerFunc(hc.Handler)
 
-	router.StrictSlash(true).Path("/datasets").HandlerFunc(dataset.GetAll(dc, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/create").HandlerFunc(dataset.GetTopics(bc)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions").HandlerFunc(dataset.GetEditions(dc)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions").HandlerFunc(dataset.GetVersions(dc, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.GetMetadataHandler(dc, zc)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.PutMetadata(dc, zc)).Methods(http.MethodPut)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(dataset.PutEditableMetadata(dc, zc)).Methods(http.MethodPut)
+	c := cache.New(cfg.CacheTTL)
+	router.StrictSlash(true).Path("/cache/stats").HandlerFunc(c.StatsHandler).Methods(http.MethodGet)
+
+	router.StrictSlash(true).Path("/datasets").HandlerFunc(c.Handler(cache.AllDatasetsID, dataset.GetAll(dc, cfg.ServiceAuthToken, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers))).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/create").HandlerFunc(c.Handler(cache.NoDatasetID, dataset.GetTopics(bc, cfg.ServiceAuthToken))).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/create").HandlerFunc(c.Invalidate(dataset.PostDataset(dc, zc, cfg.ServiceAuthToken))).Methods(http.MethodPost)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions").HandlerFunc(c.Handler(cache.RouteDatasetID, dataset.GetEditions(dc, cfg.ServiceAuthToken, cfg.DatasetsBatchWorkers))).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions").HandlerFunc(c.Invalidate(dataset.PostEdition(dc, cfg.ServiceAuthToken, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers))).Methods(http.MethodPost)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}").HandlerFunc(dataset.GetEdition(dc, cfg.ServiceAuthToken, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions").HandlerFunc(dataset.GetVersions(dc, cfg.ServiceAuthToken, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.GetMetadataHandler(dc, zc, cfg.ServiceAuthToken)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(c.Invalidate(dataset.PutMetadata(dc, zc, cfg.ServiceAuthToken))).Methods(http.MethodPut)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/legacy-metadata").HandlerFunc(dataset.GetLegacyMetadata(dc, cfg.ServiceAuthToken)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/legacy-metadata").HandlerFunc(c.Invalidate(dataset.PutLegacyMetadata(dc, zc, cfg.ServiceAuthToken))).Methods(http.MethodPut)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/diff").HandlerFunc(dataset.GetMetadataDiff(dc, cfg.ServiceAuthToken)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimensionName}").HandlerFunc(dataset.PutDimension(dc, zc, cfg.ServiceAuthToken)).Methods(http.MethodPut)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(c.Invalidate(dataset.PutEditableMetadata(dc, zc, cfg.ServiceAuthToken))).Methods(http.MethodPut)
+	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(c.Invalidate(dataset.PatchEditableMetadata(dc, zc, cfg.ServiceAuthToken))).Methods(http.MethodPatch)
+	router.StrictSlash(true).Path("/collections/{collectionID}/datasets").HandlerFunc(dataset.GetCollectionDatasets(dc, zc, cfg.ServiceAuthToken, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
+	router.StrictSlash(true).Path("/collections/{collectionID}/bulk-metadata").HandlerFunc(c.InvalidateAll(dataset.PostBulkMetadata(dc, zc, cfg.ServiceAuthToken, cfg.DatasetsBatchWorkers))).Methods(http.MethodPost)
 }